@@ -2,29 +2,111 @@ package chain
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/kristinjeanna/redact"
 )
 
+// Mode is the execution mode of a ConfiguredChainRedactor.
+type Mode int8
+
+const (
+	// SequentialMode runs each redactor on the output of the previous one.
+	SequentialMode Mode = iota
+
+	// SpanMode runs every redactor implementing redact.Detector concurrently
+	// on the original input, merges the resulting spans and applies them in
+	// a single pass. Redactors not implementing redact.Detector then run
+	// sequentially on the result, in chain order.
+	SpanMode
+)
+
+// String returns a text representation of the mode.
+func (m Mode) String() string {
+	switch m {
+	case SpanMode:
+		return "SpanMode"
+	case SequentialMode:
+		fallthrough
+	default:
+		return "SequentialMode"
+	}
+}
+
+// OverlapPolicy determines which span is kept when spans detected by
+// different redactors overlap in SpanMode.
+type OverlapPolicy int8
+
 const (
-	errMsgFmtRedactFail = "chain.Redact: an error occurred while redacting, %w"
+	// StepOrderPolicy keeps the span of the redactor appearing first in the
+	// chain.
+	StepOrderPolicy OverlapPolicy = iota
+
+	// LongestSpanPolicy keeps the longest span. Ties are broken by chain
+	// order.
+	LongestSpanPolicy
+
+	// LeftmostSpanPolicy keeps the span starting first, preferring the
+	// longest span among those starting at the same position. Remaining
+	// ties are broken by chain order.
+	LeftmostSpanPolicy
+)
+
+// String returns a text representation of the overlap policy.
+func (p OverlapPolicy) String() string {
+	switch p {
+	case LongestSpanPolicy:
+		return "LongestSpanPolicy"
+	case LeftmostSpanPolicy:
+		return "LeftmostSpanPolicy"
+	case StepOrderPolicy:
+		fallthrough
+	default:
+		return "StepOrderPolicy"
+	}
+}
+
+const (
+	errMsgFmtRedactFail = "chain.Redact: an error occurred while redacting in step %d, %v"
+	errMsgFmtMode       = "chain.NewFromOptions: unknown mode %d"
+	errMsgFmtPolicy     = "chain.NewFromOptions: unknown overlap policy %d"
 )
 
 // ChainRedactor is redactor consisting of a sequence of redactors.
 type ChainRedactor []redact.Redactor
 
-// Redact executes the redactors in the chain on the specified string.
+// Redact executes the redactors in the chain on the specified string. When
+// a redactor fails, Redact stops and returns its error as a *StepError.
 func (r ChainRedactor) Redact(s string) (string, error) {
+	redacted, errs := r.redact(s, true)
+	if len(errs) > 0 {
+		return "", errs[0]
+	}
+
+	return redacted, nil
+}
+
+// redact executes each redactor in the chain on the output of the previous
+// one, skipping failing redactors unless stopOnError is true.
+func (r ChainRedactor) redact(s string, stopOnError bool) (string, []error) {
+	var errs []error
+
 	redacted := s
-	for _, r := range r {
-		var err error
-		redacted, err = r.Redact(redacted)
+	for i, step := range r {
+		out, err := step.Redact(redacted)
 		if err != nil {
-			return "", fmt.Errorf(errMsgFmtRedactFail, err)
+			errs = append(errs, &StepError{Index: i, Redactor: step, Err: err})
+			if stopOnError {
+				break
+			}
+			continue
 		}
+		redacted = out
 	}
 
-	return redacted, nil
+	return redacted, errs
 }
 
 // New creates a new chain redactor from a slice of redactors.
@@ -36,3 +118,264 @@ func New(redactors []redact.Redactor) ChainRedactor {
 func (r ChainRedactor) String() string {
 	return fmt.Sprintf("{redactors=%q}", []redact.Redactor(r))
 }
+
+// ConfiguredChainRedactor is a chain of redactors with a configurable
+// execution mode and error policy. It is created via NewFromOptions.
+type ConfiguredChainRedactor struct {
+	redactors     ChainRedactor
+	mode          Mode
+	overlapPolicy OverlapPolicy
+	errorPolicy   ErrorPolicy
+	fallback      redact.Redactor
+}
+
+// NewFromOptions creates a new ConfiguredChainRedactor from a slice of
+// redactors with the provided options.
+func NewFromOptions(redactors []redact.Redactor, opts ...Option) (redact.Redactor, error) {
+	r := ConfiguredChainRedactor{redactors: New(redactors)}
+	for _, o := range opts {
+		o(&r)
+	}
+
+	if r.mode < SequentialMode || r.mode > SpanMode {
+		return nil, fmt.Errorf(errMsgFmtMode, r.mode)
+	}
+
+	if r.overlapPolicy < StepOrderPolicy || r.overlapPolicy > LeftmostSpanPolicy {
+		return nil, fmt.Errorf(errMsgFmtPolicy, r.overlapPolicy)
+	}
+
+	if r.errorPolicy < FailFastPolicy || r.errorPolicy > CollectErrorsPolicy {
+		return nil, fmt.Errorf(errMsgFmtErrorPolicy, r.errorPolicy)
+	}
+
+	return r, nil
+}
+
+// Redact executes the redactors in the chain on the specified string.
+//
+// When a redactor fails, the result depends on the chain's error policy.
+// Errors of failing redactors are reported as *StepError. With policies
+// other than FailFastPolicy, the returned string is redacted output that is
+// safe to use even when the returned error is not nil.
+func (r ConfiguredChainRedactor) Redact(s string) (string, error) {
+	var redacted string
+	var errs []error
+
+	if r.mode == SpanMode {
+		redacted, errs = r.redactSpans(s)
+	} else {
+		redacted, errs = r.redactors.redact(s, r.errorPolicy.stopOnError())
+	}
+
+	return r.handleErrors(s, redacted, errs)
+}
+
+// String returns a text representation of the redactor.
+func (r ConfiguredChainRedactor) String() string {
+	var b strings.Builder
+	b.WriteString("{")
+	if r.mode == SpanMode {
+		fmt.Fprintf(&b, "mode=%q; overlapPolicy=%q; ", r.mode, r.overlapPolicy)
+	}
+	if r.errorPolicy != FailFastPolicy {
+		fmt.Fprintf(&b, "errorPolicy=%q; ", r.errorPolicy)
+	}
+	if r.fallback != nil {
+		fmt.Fprintf(&b, "fallback=%q; ", r.fallback)
+	}
+	fmt.Fprintf(&b, "redactors=%q}", []redact.Redactor(r.redactors))
+
+	return b.String()
+}
+
+// candidate is a span detected by the redactor at index step of the chain.
+type candidate struct {
+	span redact.Span
+	step int
+}
+
+// redactSpans executes the chain in SpanMode.
+func (r ConfiguredChainRedactor) redactSpans(s string) (string, []error) {
+	type result struct {
+		spans []redact.Span
+		err   error
+	}
+
+	results := make([]result, len(r.redactors))
+	var wg sync.WaitGroup
+
+	for i, step := range r.redactors {
+		detector, ok := step.(redact.Detector)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, detector redact.Detector) {
+			defer wg.Done()
+			results[i].spans, results[i].err = detector.Detect(s)
+		}(i, detector)
+	}
+	wg.Wait()
+
+	var errs []error
+	var candidates []candidate
+	for i, res := range results {
+		if res.err != nil {
+			errs = append(errs, &StepError{Index: i, Redactor: r.redactors[i], Err: res.err})
+			if r.errorPolicy.stopOnError() {
+				return "", errs
+			}
+			continue
+		}
+		for _, span := range res.spans {
+			candidates = append(candidates, candidate{span: span, step: i})
+		}
+	}
+
+	redacted := redact.ApplySpans(s, r.overlapPolicy.merge(candidates))
+
+	for i, step := range r.redactors {
+		if _, ok := step.(redact.Detector); ok {
+			continue
+		}
+
+		out, err := step.Redact(redacted)
+		if err != nil {
+			errs = append(errs, &StepError{Index: i, Redactor: step, Err: err})
+			if r.errorPolicy.stopOnError() {
+				break
+			}
+			continue
+		}
+		redacted = out
+	}
+
+	return redacted, errs
+}
+
+// merge returns the non-overlapping spans to apply, ordered by position,
+// keeping the candidates preferred by the policy.
+func (p OverlapPolicy) merge(candidates []candidate) []redact.Span {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		lenA, lenB := a.span.End-a.span.Start, b.span.End-b.span.Start
+
+		switch p {
+		case LongestSpanPolicy:
+			if lenA != lenB {
+				return lenA > lenB
+			}
+		case LeftmostSpanPolicy:
+			if a.span.Start != b.span.Start {
+				return a.span.Start < b.span.Start
+			}
+			if lenA != lenB {
+				return lenA > lenB
+			}
+		}
+
+		if a.step != b.step {
+			return a.step < b.step
+		}
+		return a.span.Start < b.span.Start
+	})
+
+	var accepted []redact.Span
+	for _, c := range candidates {
+		// index of the first accepted span ordered after the candidate
+		idx := sort.Search(len(accepted), func(i int) bool {
+			return accepted[i].Start > c.span.Start ||
+				(accepted[i].Start == c.span.Start && accepted[i].End > c.span.End)
+		})
+
+		if overlapsAny(accepted, idx-1, c.span) {
+			continue
+		}
+
+		accepted = append(accepted, redact.Span{})
+		copy(accepted[idx+1:], accepted[idx:])
+		accepted[idx] = c.span
+	}
+
+	return accepted
+}
+
+// overlapsAny reports whether span overlaps any of the sorted spans
+// starting at index from.
+func overlapsAny(spans []redact.Span, from int, span redact.Span) bool {
+	if from < 0 {
+		from = 0
+	}
+
+	for i := from; i < len(spans) && spans[i].Start <= span.End; i++ {
+		if overlaps(spans[i], span) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// overlaps reports whether the spans overlap. An empty span overlaps
+// another empty span at the same position and any span strictly
+// containing its position.
+func overlaps(a, b redact.Span) bool {
+	emptyA, emptyB := a.Start == a.End, b.Start == b.End
+
+	switch {
+	case emptyA && emptyB:
+		return a.Start == b.Start
+	case emptyA:
+		return b.Start < a.Start && a.Start < b.End
+	case emptyB:
+		return a.Start < b.Start && b.Start < a.End
+	default:
+		return a.Start < b.End && b.Start < a.End
+	}
+}
+
+// Option defines options for creating new configured chain redactors.
+type Option func(*ConfiguredChainRedactor)
+
+/*
+WithMode sets the execution mode for the redactor. Default is
+"SequentialMode".
+*/
+func WithMode(mode Mode) Option {
+	return func(r *ConfiguredChainRedactor) {
+		r.mode = mode
+	}
+}
+
+/*
+WithOverlapPolicy sets the policy that resolves overlapping spans in
+"SpanMode". Default is "StepOrderPolicy".
+*/
+func WithOverlapPolicy(policy OverlapPolicy) Option {
+	return func(r *ConfiguredChainRedactor) {
+		r.overlapPolicy = policy
+	}
+}
+
+/*
+WithErrorPolicy sets the policy for handling redactors in the chain that
+return an error. Default is "FailFastPolicy".
+*/
+func WithErrorPolicy(policy ErrorPolicy) Option {
+	return func(r *ConfiguredChainRedactor) {
+		r.errorPolicy = policy
+	}
+}
+
+/*
+WithFallback sets the redactor whose output for the entire input replaces the
+chain's output when a redactor fails under "FailClosedPolicy". Default is a
+simple redactor replacing the input with "[redaction failed]".
+*/
+func WithFallback(fallback redact.Redactor) Option {
+	return func(r *ConfiguredChainRedactor) {
+		r.fallback = fallback
+	}
+}
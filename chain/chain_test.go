@@ -1,9 +1,14 @@
 package chain
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/blackout"
+	"github.com/kristinjeanna/redact/regex"
+	"github.com/kristinjeanna/redact/simple"
 	"github.com/kristinjeanna/redact/substring"
 	"github.com/kristinjeanna/redact/url"
 )
@@ -20,6 +25,24 @@ func TestNewPair_err(t *testing.T) {
 	}
 }
 
+func TestChainRedactor_slice(t *testing.T) {
+	chainRedactor := ChainRedactor{substring.New("string", "XXXXX"), url.New("http", nil)}
+
+	_, err := chainRedactor.Redact("this is a string")
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Index != 1 {
+		t.Errorf("Expected a step error for step 1, but got '%v'", err)
+	}
+
+	got, err := chainRedactor[:1].Redact("this is a string")
+	if err != nil {
+		t.Error(err)
+	}
+	if expected := "this is a XXXXX"; expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
+
 func TestString(t *testing.T) {
 	substringRedactor := substring.New("string", "XXXXX")
 	chainRedactor := New([]redact.Redactor{substringRedactor})
@@ -31,3 +54,266 @@ func TestString(t *testing.T) {
 		t.Errorf("Expected '%s', but got '%s'", expected, got)
 	}
 }
+
+func TestSpanMode(t *testing.T) {
+	type testCase struct {
+		policy   OverlapPolicy // overlap policy
+		input    string        // string to be redacted
+		expected string        // expected output
+	}
+
+	substringRedactor := substring.New("secret", "[substring]")
+	regexPair, err := regex.NewPairUsingSimple("[regex]", `(?i)top secret \w+|\[\w+\]`)
+	if err != nil {
+		t.Error(err)
+	}
+	regexRedactor, err := regex.New([]regex.Pair{*regexPair})
+	if err != nil {
+		t.Error(err)
+	}
+	redactors := []redact.Redactor{substringRedactor, regexRedactor}
+
+	cases := []testCase{
+		{StepOrderPolicy, "a top secret plan", "a top [substring] plan"},
+		{LongestSpanPolicy, "a top secret plan", "a [regex]"},
+		{LeftmostSpanPolicy, "a top secret plan", "a [regex]"},
+		{LeftmostSpanPolicy, "a secret", "a [substring]"},
+		{StepOrderPolicy, "nothing [here]", "nothing [regex]"},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("policy=%q;input=%q;expected=%q; ", tc.policy, tc.input, tc.expected), func(t *testing.T) {
+			r, err := NewFromOptions(redactors, WithMode(SpanMode), WithOverlapPolicy(tc.policy))
+			if err != nil {
+				t.Error(err)
+			}
+			got, err := r.Redact(tc.input)
+			if err != nil {
+				t.Error(err)
+			}
+			if tc.expected != got {
+				t.Errorf("Expected '%s', but got '%s'", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSpanMode_nonDetectorSteps(t *testing.T) {
+	redactors := []redact.Redactor{blackout.New("*"), substring.New("secret", "[x]")}
+	r, err := NewFromOptions(redactors, WithMode(SpanMode))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// detectors run first, the blackout redactor then runs on their output
+	expected := "** *** **"
+	got, err := r.Redact("my secret is")
+	if err != nil {
+		t.Error(err)
+	}
+	if expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
+
+func TestSpanMode_doesNotRescanReplacements(t *testing.T) {
+	first := substring.New("password", "[secret]")
+	second := substring.New("secret", "XXXXX")
+
+	sequential := New([]redact.Redactor{first, second})
+	got, err := sequential.Redact("password")
+	if err != nil {
+		t.Error(err)
+	}
+	if got != "[XXXXX]" {
+		t.Errorf("Expected '%s', but got '%s'", "[XXXXX]", got)
+	}
+
+	spans, err := NewFromOptions([]redact.Redactor{first, second}, WithMode(SpanMode))
+	if err != nil {
+		t.Error(err)
+	}
+	got, err = spans.Redact("password")
+	if err != nil {
+		t.Error(err)
+	}
+	if got != "[secret]" {
+		t.Errorf("Expected '%s', but got '%s'", "[secret]", got)
+	}
+}
+
+func TestSpanMode_err(t *testing.T) {
+	failing, err := substring.NewFromOptions("string", substring.WithRedactor(url.New("http", nil)))
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, redactors := range [][]redact.Redactor{
+		{substring.New("is", "XX"), failing},
+		{substring.New("is", "XX"), url.New("http", nil)},
+	} {
+		r, err := NewFromOptions(redactors, WithMode(SpanMode))
+		if err != nil {
+			t.Error(err)
+		}
+		_, err = r.Redact("this is a string")
+		if err == nil {
+			t.Error("Expected an error, but got nil")
+		}
+	}
+}
+
+func TestNewFromOptions_err(t *testing.T) {
+	_, err := NewFromOptions(nil, WithMode(Mode(42)))
+	if err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+
+	_, err = NewFromOptions(nil, WithOverlapPolicy(OverlapPolicy(42)))
+	if err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+
+	_, err = NewFromOptions(nil, WithErrorPolicy(ErrorPolicy(42)))
+	if err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+}
+
+func TestString_spanMode(t *testing.T) {
+	substringRedactor := substring.New("string", "XXXXX")
+	chainRedactor, err := NewFromOptions([]redact.Redactor{substringRedactor},
+		WithMode(SpanMode), WithOverlapPolicy(LongestSpanPolicy))
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := `{mode="SpanMode"; overlapPolicy="LongestSpanPolicy"; redactors=["{substring=\"string\"; replacement=\"XXXXX\"}"]}`
+	got := fmt.Sprint(chainRedactor)
+
+	if expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
+
+func TestErrorPolicies(t *testing.T) {
+	type testCase struct {
+		mode     Mode        // execution mode
+		policy   ErrorPolicy // error policy
+		expected string      // expected output
+		errCount int         // expected number of step errors
+	}
+
+	failing, err := substring.NewFromOptions("a", substring.WithRedactor(url.New("http", nil)))
+	if err != nil {
+		t.Error(err)
+	}
+	redactors := []redact.Redactor{
+		substring.New("this", "XXXX"),
+		failing,
+		url.New("http", nil),
+		substring.New("string", "YYYYYY"),
+	}
+
+	cases := []testCase{
+		{SequentialMode, FailFastPolicy, "", 1},
+		{SequentialMode, FailClosedPolicy, "[failed]", 1},
+		{SequentialMode, SkipStepPolicy, "XXXX is a YYYYYY", 0},
+		{SequentialMode, CollectErrorsPolicy, "XXXX is a YYYYYY", 2},
+		{SpanMode, FailFastPolicy, "", 1},
+		{SpanMode, FailClosedPolicy, "[failed]", 1},
+		{SpanMode, SkipStepPolicy, "XXXX is a YYYYYY", 0},
+		{SpanMode, CollectErrorsPolicy, "XXXX is a YYYYYY", 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("mode=%q;policy=%q;expected=%q; ", tc.mode, tc.policy, tc.expected), func(t *testing.T) {
+			r, err := NewFromOptions(redactors,
+				WithMode(tc.mode),
+				WithErrorPolicy(tc.policy),
+				WithFallback(simple.New("[failed]")),
+			)
+			if err != nil {
+				t.Error(err)
+			}
+
+			got, err := r.Redact("this is a string")
+			if tc.expected != got {
+				t.Errorf("Expected '%s', but got '%s'", tc.expected, got)
+			}
+
+			var stepErrs []*StepError
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, e := range joined.Unwrap() {
+					stepErrs = append(stepErrs, e.(*StepError))
+				}
+			} else if err != nil {
+				var stepErr *StepError
+				if !errors.As(err, &stepErr) {
+					t.Fatalf("Expected a *StepError, but got '%v'", err)
+				}
+				stepErrs = append(stepErrs, stepErr)
+			}
+
+			if len(stepErrs) != tc.errCount {
+				t.Fatalf("Expected %d step errors, but got %d: %v", tc.errCount, len(stepErrs), err)
+			}
+			if tc.errCount > 0 && (stepErrs[0].Index != 1 || stepErrs[0].Redactor != failing) {
+				t.Errorf("Expected step 1 to fail, but got step %d", stepErrs[0].Index)
+			}
+			if tc.errCount > 1 && stepErrs[1].Index != 2 {
+				t.Errorf("Expected step 2 to fail, but got step %d", stepErrs[1].Index)
+			}
+		})
+	}
+}
+
+func TestErrorPolicies_defaultFallback(t *testing.T) {
+	r, err := NewFromOptions([]redact.Redactor{url.New("http", nil)}, WithErrorPolicy(FailClosedPolicy))
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := defaultFallbackText
+	got, err := r.Redact("this is a string")
+	if err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+	if expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
+
+func TestErrorPolicies_fallbackFails(t *testing.T) {
+	r, err := NewFromOptions([]redact.Redactor{url.New("http", nil)},
+		WithErrorPolicy(FailClosedPolicy),
+		WithFallback(url.New("http", nil)),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	got, err := r.Redact("this is a string")
+	if err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+	if got != "" {
+		t.Errorf("Expected '', but got '%s'", got)
+	}
+}
+
+func TestString_errorPolicy(t *testing.T) {
+	chainRedactor, err := NewFromOptions([]redact.Redactor{substring.New("string", "XXXXX")},
+		WithErrorPolicy(FailClosedPolicy), WithFallback(simple.New("[failed]")))
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := `{errorPolicy="FailClosedPolicy"; fallback="{replacement=\"[failed]\"}"; ` +
+		`redactors=["{substring=\"string\"; replacement=\"XXXXX\"}"]}`
+	got := fmt.Sprint(chainRedactor)
+
+	if expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
@@ -1,3 +1,4 @@
 // Package chain provides the ChainRedactor. The ChainRedactor consists of a slice of redactors
-// that each redact an input string in the order that they appear in the slice.
+// that each redact an input string in the order that they appear in the slice. The
+// ConfiguredChainRedactor, created via NewFromOptions, adds execution modes and error policies.
 package chain
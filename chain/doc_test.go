@@ -32,3 +32,36 @@ func ExampleChainRedactor() {
 	fmt.Println(result)
 	// Output: this [redacted] HIDES [redacted] information
 }
+
+func ExampleNewFromOptions() {
+	// in span mode, each redactor sees the original input, so the regex
+	// redactor never matches the text inserted by the substring redactor
+	substringRedactor := substring.New("password", "[secret]")
+
+	regexPair, err := regex.NewPairUsingSimple("XXXXX", `secret|\d{4}`)
+	if err != nil {
+		log.Fatalf("an error occurred while creating regex pair: %s", err)
+	}
+	regexRedactor, err := regex.New([]regex.Pair{*regexPair})
+	if err != nil {
+		log.Fatalf("an error occurred while creating regex redactor: %s", err)
+	}
+
+	chainRedactor, err := NewFromOptions(
+		[]redact.Redactor{substringRedactor, regexRedactor},
+		WithMode(SpanMode),
+		WithOverlapPolicy(LongestSpanPolicy),
+	)
+	if err != nil {
+		log.Fatalf("an error occurred while creating chain redactor: %s", err)
+	}
+	sampleString := "the password for 1234 is secret"
+
+	result, err := chainRedactor.Redact(sampleString)
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+
+	fmt.Println(result)
+	// Output: the [secret] for XXXXX is XXXXX
+}
@@ -0,0 +1,110 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+// ErrorPolicy determines how a ConfiguredChainRedactor handles a redactor in
+// the chain returning an error.
+type ErrorPolicy int8
+
+const (
+	// FailFastPolicy stops at the first failing redactor and returns an
+	// empty string along with its error.
+	FailFastPolicy ErrorPolicy = iota
+
+	// FailClosedPolicy stops at the first failing redactor and returns the
+	// output of the fallback redactor for the entire input along with the
+	// error.
+	FailClosedPolicy
+
+	// SkipStepPolicy skips failing redactors and returns the output of the
+	// remaining ones without an error.
+	SkipStepPolicy
+
+	// CollectErrorsPolicy skips failing redactors and returns the output of
+	// the remaining ones along with the errors of all failing redactors,
+	// joined via errors.Join.
+	CollectErrorsPolicy
+)
+
+// String returns a text representation of the error policy.
+func (p ErrorPolicy) String() string {
+	switch p {
+	case FailClosedPolicy:
+		return "FailClosedPolicy"
+	case SkipStepPolicy:
+		return "SkipStepPolicy"
+	case CollectErrorsPolicy:
+		return "CollectErrorsPolicy"
+	case FailFastPolicy:
+		fallthrough
+	default:
+		return "FailFastPolicy"
+	}
+}
+
+const (
+	defaultFallbackText = "[redaction failed]"
+
+	errMsgFmtErrorPolicy  = "chain.NewFromOptions: unknown error policy %d"
+	errMsgFmtFallbackFail = "chain.Redact: an error occurred while running the fallback redactor, %w"
+)
+
+// StepError is the error returned by a ChainRedactor or a
+// ConfiguredChainRedactor when one of the redactors in the chain fails.
+type StepError struct {
+	Index    int             // index of the failing redactor in the chain
+	Redactor redact.Redactor // the failing redactor
+	Err      error           // the error returned by the redactor
+}
+
+// Error returns the error message.
+func (e *StepError) Error() string {
+	return fmt.Sprintf(errMsgFmtRedactFail, e.Index, e.Err)
+}
+
+// Unwrap returns the error returned by the failing redactor.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// stopOnError reports whether the policy stops executing the chain at the
+// first failing redactor.
+func (p ErrorPolicy) stopOnError() bool {
+	return p == FailFastPolicy || p == FailClosedPolicy
+}
+
+// handleErrors returns the result of redacting s according to the error
+// policy, given the output of the chain and the errors of its failing
+// redactors.
+func (r ConfiguredChainRedactor) handleErrors(s string, redacted string, errs []error) (string, error) {
+	if len(errs) == 0 {
+		return redacted, nil
+	}
+
+	switch r.errorPolicy {
+	case FailClosedPolicy:
+		fallback := r.fallback
+		if fallback == nil {
+			fallback = simple.New(defaultFallbackText)
+		}
+		out, err := fallback.Redact(s)
+		if err != nil {
+			return "", errors.Join(errs[0], fmt.Errorf(errMsgFmtFallbackFail, err))
+		}
+		return out, errs[0]
+	case SkipStepPolicy:
+		return redacted, nil
+	case CollectErrorsPolicy:
+		return redacted, errors.Join(errs...)
+	case FailFastPolicy:
+		fallthrough
+	default:
+		return "", errs[0]
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Command redact redacts sensitive information from files or standard input.
+
+Usage:
+
+	redact [flags] [file ...]
+	redact scan [flags] path ...
+	redact sanitize [flags] -o output path ...
+
+With no files, or when a file is "-", redact reads standard input. Redacted
+output is written to standard output, or back to each file with -in-place.
+Rules are specified via flags, which may be repeated, or via a rules file:
+
+	-regex pattern       redact matches of the regular expression
+	-substring text      redact occurrences of the text
+	-preset names        redact matches of the comma-separated presets
+	                     (auth-header, ssn)
+	-url                 redact passwords in URLs
+	-rules file          read rules from the file
+
+The following flags control how input is redacted:
+
+	-replacement text    replacement text (default "[redacted]")
+	-middle              keep the start and end of each match, replacing
+	                     only its middle with the replacement text
+	-whole               redact each input as a whole rather than line by
+	                     line, so rules can match across lines
+	-in-place            write the redacted output back to each file
+	-check               write no output, and exit with status 1 if anything
+	                     would be redacted
+
+Each line of a rules file holds one rule, whose kind is separated from its
+value by spaces or tabs; empty lines and lines starting with "#" are ignored:
+
+	regex <pattern>
+	substring <text>
+	preset <name>[,<name>...]
+	url
+
+The scan and sanitize subcommands walk directories and .tar, .tar.gz, .tgz
+and .zip archives, redacting every text file they contain. Files are
+detected as binary, and left unredacted, when their first 8000 bytes contain
+a NUL byte. Both subcommands print a summary of the files with findings and
+of the skipped binary files. Scan writes nothing else, and exits with status 1
+if anything would be redacted. Sanitize writes a sanitized copy of each path
+with the same structure, to the output path given via -o or, for several
+paths, to the directory given via -o. Binary files are copied unchanged.
+*/
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	exitOK       = 0
+	exitRedacted = 1 // -check found something to redact
+	exitError    = 2
+
+	defaultReplacement = "[redacted]"
+)
+
+var errInPlaceStdin = errors.New("-in-place cannot be used with standard input")
+
+// stringsFlag is a flag.Value collecting the values of a repeated flag.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(s string) error {
+	*f = append(*f, s)
+	return nil
+}
+
+// ruleFlags holds the flags specifying redaction rules, which are shared
+// by all subcommands.
+type ruleFlags struct {
+	config     config
+	regexes    stringsFlag
+	substrings stringsFlag
+	presets    stringsFlag
+	rulesFiles stringsFlag
+	whole      bool
+}
+
+// register defines the flags on the flag set.
+func (f *ruleFlags) register(fs *flag.FlagSet) {
+	fs.Var(&f.regexes, "regex", "redact matches of the regular `pattern` (repeatable)")
+	fs.Var(&f.substrings, "substring", "redact occurrences of the `text` (repeatable)")
+	fs.Var(&f.presets, "preset", "redact matches of the comma-separated preset `names`: "+strings.Join(presetNames(), ", "))
+	fs.Var(&f.rulesFiles, "rules", "read redaction rules from the `file` (repeatable)")
+	fs.BoolVar(&f.config.url, "url", false, "redact passwords in URLs")
+	fs.BoolVar(&f.config.middle, "middle", false, "keep the start and end of each match, replacing only its middle")
+	fs.StringVar(&f.config.replacement, "replacement", defaultReplacement, "replacement `text`")
+	fs.BoolVar(&f.whole, "whole", false, "redact each input as a whole rather than line by line")
+}
+
+// processor returns a processor for the rules specified by the flags.
+func (f *ruleFlags) processor() (processor, error) {
+	c := f.config
+	c.regexes = f.regexes
+	c.substrings = f.substrings
+	for _, p := range f.presets {
+		c.presets = append(c.presets, splitList(p)...)
+	}
+	for _, path := range f.rulesFiles {
+		if err := c.readRulesFile(path); err != nil {
+			return processor{}, err
+		}
+	}
+
+	redactor, err := c.build()
+	if err != nil {
+		return processor{}, err
+	}
+	return processor{redactor: redactor, whole: f.whole}, nil
+}
+
+// newFlagSet returns a flag set for the command or subcommand with the
+// specified usage line.
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: "+usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// options holds the parsed command line.
+type options struct {
+	rules   ruleFlags
+	inPlace bool
+	check   bool
+	files   []string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command with the specified arguments and returns its
+// exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "scan":
+			return runScan(args[1:], false, stdout, stderr)
+		case "sanitize":
+			return runScan(args[1:], true, stdout, stderr)
+		}
+	}
+
+	opts, err := parseArgs(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "redact: %s\n", err)
+		return exitError
+	}
+
+	p, err := opts.rules.processor()
+	if err != nil {
+		fmt.Fprintf(stderr, "redact: %s\n", err)
+		return exitError
+	}
+
+	status := exitOK
+	for _, name := range opts.files {
+		found, err := opts.process(p, name, stdin, stdout, stderr)
+		if err != nil {
+			fmt.Fprintf(stderr, "redact: %s: %s\n", name, err)
+			return exitError
+		}
+		if found && opts.check {
+			status = exitRedacted
+		}
+	}
+
+	return status
+}
+
+// parseArgs parses the command line.
+func parseArgs(args []string, stderr io.Writer) (options, error) {
+	var opts options
+
+	fs := newFlagSet("redact", "redact [flags] [file ...]\n"+
+		"       redact scan [flags] path ...\n"+
+		"       redact sanitize [flags] -o output path ...", stderr)
+	opts.rules.register(fs)
+	fs.BoolVar(&opts.inPlace, "in-place", false, "write the redacted output back to each file")
+	fs.BoolVar(&opts.check, "check", false, "write no output; exit with status 1 if anything would be redacted")
+
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+
+	opts.files = fs.Args()
+	if len(opts.files) == 0 {
+		opts.files = []string{"-"}
+	}
+
+	if opts.inPlace {
+		for _, name := range opts.files {
+			if name == "-" {
+				return opts, errInPlaceStdin
+			}
+		}
+	}
+
+	return opts, nil
+}
+
+// process redacts the named file, or standard input if the name is "-",
+// and reports whether anything was redacted.
+func (o options) process(p processor, name string, stdin io.Reader, stdout, stderr io.Writer) (bool, error) {
+	if name == "-" {
+		return o.processReader(p, "<stdin>", stdin, stdout, stderr)
+	}
+
+	if o.inPlace && !o.check {
+		return p.redactFile(name)
+	}
+
+	f, err := os.Open(name) // #nosec G304 -- the path is provided by the user
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	return o.processReader(p, name, f, stdout, stderr)
+}
+
+// processReader redacts the input read from r, writing the output to
+// stdout or, in check mode, the locations of findings to stderr.
+func (o options) processReader(p processor, name string, r io.Reader, stdout, stderr io.Writer) (bool, error) {
+	if !o.check {
+		redacted, err := p.redact(r, stdout)
+		return redacted > 0, err
+	}
+
+	findings, err := p.check(r)
+	if err != nil {
+		return false, err
+	}
+	for _, line := range findings {
+		if line == 0 {
+			fmt.Fprintf(stderr, "%s: would be redacted\n", name)
+		} else {
+			fmt.Fprintf(stderr, "%s:%d: would be redacted\n", name, line)
+		}
+	}
+
+	return len(findings) > 0, nil
+}
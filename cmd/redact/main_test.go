@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	var tests = []struct {
+		args     []string
+		input    string
+		expected string
+		status   int
+	}{
+		{[]string{"-substring", "secret"}, "a secret\nno\n", "a [redacted]\nno\n", exitOK},
+		{[]string{"-substring", "secret"}, "a secret\r\nno", "a [redacted]\r\nno", exitOK},
+		{[]string{"-regex", `\d+`, "-replacement", "$1#"}, "pin 1234\n", "pin $1#\n", exitOK},
+		{[]string{"-preset", "ssn,auth-header"}, "Authorization: Basic Zm9v\nssn=123-45-6789\n", "Authorization: [redacted]\nssn=[redacted]\n", exitOK},
+		{[]string{"-url"}, "dsn=postgres://admin:hunter2@db:5432/app\n", "dsn=postgres://admin:%5Bredacted%5D@db:5432/app\n", exitOK},
+		{[]string{"-middle", "-replacement", "***", "-substring", "0123456789"}, "key 0123456789\n", "key 012***789\n", exitOK},
+		{[]string{"-regex", `(?s)BEGIN.*END`}, "BEGIN\nkey\nEND\n", "BEGIN\nkey\nEND\n", exitOK},
+		{[]string{"-whole", "-regex", `(?s)BEGIN.*END`}, "BEGIN\nkey\nEND\n", "[redacted]\n", exitOK},
+		{[]string{"-check", "-substring", "secret"}, "a secret\n", "", exitRedacted},
+		{[]string{"-check", "-substring", "secret"}, "nothing\n", "", exitOK},
+		{[]string{}, "a secret\n", "", exitError},
+		{[]string{"-preset", "bogus"}, "a secret\n", "", exitError},
+		{[]string{"-regex", "("}, "a secret\n", "", exitError},
+		{[]string{"-in-place", "-substring", "secret"}, "a secret\n", "", exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("args=%q;input=%q", tt.args, tt.input), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, strings.NewReader(tt.input), &stdout, &stderr)
+
+			if status != tt.status {
+				t.Errorf("Expected status %d, but got %d (stderr: %s)", tt.status, status, stderr.String())
+			}
+			if stdout.String() != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRun_checkReportsLines(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	input := "one\ntwo secret\nthree\nsecret four\n"
+
+	status := run([]string{"-check", "-substring", "secret"}, strings.NewReader(input), &stdout, &stderr)
+	if status != exitRedacted {
+		t.Errorf("Expected status %d, but got %d", exitRedacted, status)
+	}
+
+	expected := "<stdin>:2: would be redacted\n<stdin>:4: would be redacted\n"
+	if stderr.String() != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("Expected no output, but got '%s'", stdout.String())
+	}
+}
+
+func TestRun_inPlace(t *testing.T) {
+	dir := t.TempDir()
+	changed := filepath.Join(dir, "changed.log")
+	unchanged := filepath.Join(dir, "unchanged.log")
+
+	if err := os.WriteFile(changed, []byte("token=abc123\nok\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(unchanged, []byte("ok\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"-in-place", "-regex", `abc\d+`, changed, unchanged}, nil, &stdout, &stderr)
+	if status != exitOK {
+		t.Fatalf("Expected status %d, but got %d (stderr: %s)", exitOK, status, stderr.String())
+	}
+
+	var tests = []struct {
+		path     string
+		expected string
+		perm     os.FileMode
+	}{
+		{changed, "token=[redacted]\nok\n", 0o640},
+		{unchanged, "ok\n", 0o600},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("path=%q", filepath.Base(tt.path)), func(t *testing.T) {
+			b, err := os.ReadFile(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, string(b))
+			}
+
+			info, err := os.Stat(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != tt.perm {
+				t.Errorf("Expected mode %v, but got %v", tt.perm, info.Mode().Perm())
+			}
+		})
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 files, but got %d", len(entries))
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("Expected no output, but got '%s'", stdout.String())
+	}
+}
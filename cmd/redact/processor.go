@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+// processor applies a redactor to streams, either line by line or to each
+// stream as a whole.
+type processor struct {
+	redactor redact.Redactor
+	whole    bool
+}
+
+// redact writes the redacted contents of r to w and returns the number of
+// redacted lines, which in whole mode is 1 if anything was redacted.
+func (p processor) redact(r io.Reader, w io.Writer) (int, error) {
+	redacted := 0
+	err := p.each(r, func(_ int, in, out string) error {
+		if in != out {
+			redacted++
+		}
+		_, err := io.WriteString(w, out)
+		return err
+	})
+
+	return redacted, err
+}
+
+// check returns the numbers of the lines of r that would be redacted, or
+// [0] in whole mode if anything would be.
+func (p processor) check(r io.Reader) ([]int, error) {
+	var findings []int
+	err := p.each(r, func(n int, in, out string) error {
+		if in != out {
+			findings = append(findings, n)
+		}
+		return nil
+	})
+
+	return findings, err
+}
+
+// each redacts r line by line, or as a whole, calling fn with the 1-based
+// line number (0 in whole mode), the input and the redacted output.
+func (p processor) each(r io.Reader, fn func(n int, in, out string) error) error {
+	if p.whole {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		out, err := p.redactor.Redact(string(b))
+		if err != nil {
+			return err
+		}
+		return fn(0, string(b), out)
+	}
+
+	br := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, err := br.ReadString('\n')
+		if len(line) > 0 {
+			out, rerr := p.redactLine(line)
+			if rerr != nil {
+				return rerr
+			}
+			if ferr := fn(n, line, out); ferr != nil {
+				return ferr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// redactLine redacts a line, leaving its line ending intact.
+func (p processor) redactLine(line string) (string, error) {
+	content := strings.TrimSuffix(line, "\n")
+	content = strings.TrimSuffix(content, "\r")
+	ending := line[len(content):]
+
+	out, err := p.redactor.Redact(content)
+	if err != nil {
+		return "", err
+	}
+	return out + ending, nil
+}
+
+// redactFile redacts the file at path in place and reports whether
+// anything was redacted. The redacted output is written to a temporary file
+// that then replaces the original, so the file is never left half written.
+func (p processor) redactFile(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	in, err := os.Open(path) // #nosec G304 -- the path is provided by the user
+	if err != nil {
+		return false, err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".redact-*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	w := bufio.NewWriter(tmp)
+	redacted, err := p.redact(in, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if redacted == 0 {
+		return false, nil
+	}
+
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp.Name(), path)
+}
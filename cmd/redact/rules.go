@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/chain"
+	"github.com/kristinjeanna/redact/middle"
+	"github.com/kristinjeanna/redact/regex"
+	"github.com/kristinjeanna/redact/simple"
+	"github.com/kristinjeanna/redact/substring"
+	"github.com/kristinjeanna/redact/url"
+)
+
+const (
+	// regex for URLs with a password in their user info
+	urlWithPasswordRegex = `[a-zA-Z][a-zA-Z0-9+.\-]*://[^\s:/@]+:[^\s/@]*@\S*`
+
+	// regex for an HTTP authorization header, capturing its value in group 2
+	authHeaderValueRegex = `(?i)(Authorization[\s]*:[\s]*)(.*)`
+)
+
+var (
+	errNoRules = errors.New("no redaction rules specified")
+
+	errMsgFmtUnknownPreset = "unknown preset %q (available: %s)"
+	errMsgFmtRulesFile     = "%s:%d: %s"
+)
+
+// presets maps preset names to functions creating the corresponding pair
+// for a redactor.
+var presets = map[string]func(r redact.Redactor) (*regex.Pair, error){
+	"ssn": func(r redact.Redactor) (*regex.Pair, error) {
+		return regex.NewPair(r, regex.SSNRegex)
+	},
+	"auth-header": func(r redact.Redactor) (*regex.Pair, error) {
+		return regex.NewGroupPair(r, authHeaderValueRegex, "2")
+	},
+}
+
+// config holds the redaction rules specified on the command line and in
+// rules files.
+type config struct {
+	regexes     []string
+	substrings  []string
+	presets     []string
+	url         bool
+	middle      bool
+	replacement string
+}
+
+// matchRedactor returns the redactor applied to each match of a rule.
+func (c config) matchRedactor() (redact.Redactor, error) {
+	if c.middle {
+		return middle.NewFromOptions(middle.WithReplacementText(c.replacement))
+	}
+	return simple.New(c.replacement), nil
+}
+
+// build returns a redactor for the configured rules.
+func (c config) build() (redact.Redactor, error) {
+	matchRedactor, err := c.matchRedactor()
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []redact.Redactor
+	for _, s := range c.substrings {
+		r, err := substring.NewFromOptions(s, substring.WithRedactor(matchRedactor))
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, r)
+	}
+
+	var pairs []regex.Pair
+	for _, name := range c.presets {
+		newPair, ok := presets[name]
+		if !ok {
+			return nil, fmt.Errorf(errMsgFmtUnknownPreset, name, strings.Join(presetNames(), ", "))
+		}
+		pair, err := newPair(literalRedactor{matchRedactor})
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair.Named(name))
+	}
+
+	for _, re := range c.regexes {
+		// simple.SimpleRedactor would expand "$" in the replacement text
+		pair, err := regex.NewPair(literalRedactor{matchRedactor}, re)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, *pair)
+	}
+
+	if c.url {
+		pair, err := regex.NewPair(url.New(c.replacement, nil), urlWithPasswordRegex)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, *pair)
+	}
+
+	if len(pairs) > 0 {
+		r, err := regex.New(pairs)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, r)
+	}
+
+	if len(steps) == 0 {
+		return nil, errNoRules
+	}
+	return chain.New(steps), nil
+}
+
+// literalRedactor hides the type of a redactor from the regex package so
+// that the replacement text of a simple redactor is used literally.
+type literalRedactor struct {
+	redact.Redactor
+}
+
+// presetNames returns the sorted names of the available presets.
+func presetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+readRulesFile adds the rules in the file at path to the config. Each
+non-empty line not starting with "#" holds one rule, whose kind is separated
+from its value by spaces or tabs:
+
+	regex <pattern>
+	substring <text>
+	preset <name>[,<name>...]
+	url
+*/
+func (c *config) readRulesFile(path string) error {
+	f, err := os.Open(path) // #nosec G304 -- the path is provided by the user
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kind, value := cutRule(line)
+
+		switch {
+		case kind == "url" && value == "":
+			c.url = true
+		case value == "":
+			return fmt.Errorf(errMsgFmtRulesFile, path, n, "missing value for rule "+kind)
+		case kind == "regex":
+			c.regexes = append(c.regexes, value)
+		case kind == "substring":
+			c.substrings = append(c.substrings, value)
+		case kind == "preset":
+			c.presets = append(c.presets, splitList(value)...)
+		default:
+			return fmt.Errorf(errMsgFmtRulesFile, path, n, "unknown rule "+kind)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// cutRule splits a rule line at its first run of whitespace into the kind of
+// the rule and its value, which keeps any further whitespace verbatim.
+func cutRule(line string) (kind, value string) {
+	i := strings.IndexFunc(line, unicode.IsSpace)
+	if i < 0 {
+		return line, ""
+	}
+	return line[:i], strings.TrimLeftFunc(line[i:], unicode.IsSpace)
+}
+
+// splitList splits a comma-separated list, dropping empty elements.
+func splitList(s string) []string {
+	var list []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadRulesFile(t *testing.T) {
+	var tests = []struct {
+		content  string
+		expected config
+		errMsg   string
+	}{
+		{
+			"# rules\n\nregex \\d{4}\nsubstring top secret\npreset ssn, auth-header\nurl\n",
+			config{
+				regexes:    []string{`\d{4}`},
+				substrings: []string{"top secret"},
+				presets:    []string{"ssn", "auth-header"},
+				url:        true,
+			},
+			"",
+		},
+		{
+			"regex\t\\d{4}\nsubstring   top  secret\npreset \t ssn\nurl\t\n",
+			config{
+				regexes:    []string{`\d{4}`},
+				substrings: []string{"top  secret"},
+				presets:    []string{"ssn"},
+				url:        true,
+			},
+			"",
+		},
+		{"regex \\d\t\\d\n", config{regexes: []string{"\\d\t\\d"}}, ""},
+		{"regex\n", config{}, "rules:1: missing value for rule regex"},
+		{"regex \t \n", config{}, "rules:1: missing value for rule regex"},
+		{"\nglob *.key\n", config{}, "rules:2: unknown rule glob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("content=%q", tt.content), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var c config
+			err := c.readRulesFile(path)
+			if tt.errMsg != "" {
+				if err == nil || !strings.HasSuffix(err.Error(), tt.errMsg) {
+					t.Errorf("Expected error ending with '%s', but got '%v'", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(c, tt.expected) {
+				t.Errorf("Expected '%+v', but got '%+v'", tt.expected, c)
+			}
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected []string
+	}{
+		{"ssn", []string{"ssn"}},
+		{"ssn,auth-header", []string{"ssn", "auth-header"}},
+		{" ssn , ,auth-header,", []string{"ssn", "auth-header"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual := splitList(tt.input)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Expected '%q', but got '%q'", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,477 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// sniffLen is the number of leading bytes searched for a NUL byte to detect
+// binary files, as done by git.
+const sniffLen = 8000
+
+var (
+	errNoPaths        = errors.New("no paths specified")
+	errOutputRequired = errors.New("an output path must be specified via -o")
+
+	errMsgFmtOutputExists       = "output %s already exists"
+	errMsgFmtOutputInsideSource = "output %s must not be inside %s"
+)
+
+// archiveKind identifies the format of an archive by its file name.
+type archiveKind int8
+
+const (
+	notArchive archiveKind = iota
+	tarArchive
+	tarGzArchive
+	zipArchive
+)
+
+// archiveKindOf returns the kind of archive the named file is.
+func archiveKindOf(name string) archiveKind {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return tarGzArchive
+	case strings.HasSuffix(lower, ".tar"):
+		return tarArchive
+	case strings.HasSuffix(lower, ".zip"):
+		return zipArchive
+	default:
+		return notArchive
+	}
+}
+
+// isBinary reports whether data looks like the contents of a binary file.
+func isBinary(data []byte) bool {
+	if len(data) > sniffLen {
+		data = data[:sniffLen]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// runScan executes the scan subcommand or, if sanitize is true, the
+// sanitize subcommand, and returns the exit status.
+func runScan(args []string, sanitize bool, stdout, stderr io.Writer) int {
+	var rules ruleFlags
+	var output string
+
+	name, usage := "scan", "redact scan [flags] path ..."
+	if sanitize {
+		name, usage = "sanitize", "redact sanitize [flags] -o output path ..."
+	}
+
+	flags := newFlagSet("redact "+name, usage, stderr)
+	rules.register(flags)
+	if sanitize {
+		flags.StringVar(&output, "o", "", "write the sanitized copy to `path`, a directory if several paths are specified")
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		fmt.Fprintf(stderr, "redact: %s\n", err)
+		return exitError
+	}
+
+	s, err := newScanner(rules, flags.Args(), sanitize, output, stdout)
+	if err == nil {
+		err = s.run()
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "redact: %s\n", err)
+		return exitError
+	}
+
+	if !sanitize && s.withFindings > 0 {
+		return exitRedacted
+	}
+	return exitOK
+}
+
+// scanner redacts the files in directories and archives, writing a summary
+// of the findings.
+type scanner struct {
+	p       processor
+	paths   []string
+	targets []string // sanitized copies of paths, empty when scanning
+	created string   // output directory created for the targets, if any
+	summary io.Writer
+
+	files        int
+	withFindings int
+	binaries     int
+}
+
+// newScanner returns a scanner for the paths. If sanitize is true, the
+// sanitized copies are written to output.
+func newScanner(rules ruleFlags, paths []string, sanitize bool, output string, summary io.Writer) (*scanner, error) {
+	if len(paths) == 0 {
+		return nil, errNoPaths
+	}
+
+	p, err := rules.processor()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &scanner{p: p, paths: paths, summary: summary}
+	if !sanitize {
+		return s, nil
+	}
+
+	if output == "" {
+		return nil, errOutputRequired
+	}
+
+	if len(paths) == 1 {
+		s.targets = []string{output}
+	} else {
+		for _, p := range paths {
+			s.targets = append(s.targets, filepath.Join(output, filepath.Base(p)))
+		}
+	}
+
+	for _, target := range s.targets {
+		if _, err := os.Lstat(target); err == nil {
+			return nil, fmt.Errorf(errMsgFmtOutputExists, target)
+		}
+
+		// a copy written inside its source would be walked again itself
+		for _, src := range paths {
+			inside, err := isInside(target, src)
+			if err != nil {
+				return nil, err
+			}
+			if inside {
+				return nil, fmt.Errorf(errMsgFmtOutputInsideSource, target, src)
+			}
+		}
+	}
+
+	if len(paths) > 1 {
+		if _, err := os.Lstat(output); errors.Is(err, fs.ErrNotExist) {
+			s.created = output
+		}
+		if err := os.MkdirAll(output, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// run scans every path and writes the totals to the summary. If scanning
+// fails, the partially written sanitized copies are removed.
+func (s *scanner) run() error {
+	for i, src := range s.paths {
+		dst := ""
+		if s.targets != nil {
+			dst = s.targets[i]
+		}
+
+		if err := s.scan(src, dst); err != nil {
+			s.removeTargets()
+			return err
+		}
+	}
+
+	fmt.Fprintf(s.summary, "%d files scanned, %d with findings, %d binary skipped\n",
+		s.files, s.withFindings, s.binaries)
+	return nil
+}
+
+// removeTargets removes the sanitized copies written so far, along with the
+// output directory if it was created for them.
+func (s *scanner) removeTargets() {
+	for _, target := range s.targets {
+		os.RemoveAll(target)
+	}
+	if s.created != "" {
+		os.Remove(s.created)
+	}
+}
+
+// scan scans the file or directory at src, writing its sanitized copy to
+// dst unless dst is empty.
+func (s *scanner) scan(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return s.scanFile(src, dst, info.Mode().Perm())
+	}
+
+	return filepath.WalkDir(src, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		target := ""
+		if dst != "" {
+			rel, err := filepath.Rel(src, name)
+			if err != nil {
+				return err
+			}
+			target = filepath.Join(dst, rel)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			if target == "" {
+				return nil
+			}
+			// keep the directory writable so its contents can be copied
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case d.Type().IsRegular():
+			return s.scanFile(name, target, info.Mode().Perm())
+		default:
+			fmt.Fprintf(s.summary, "%s: skipped (not a regular file)\n", name)
+			return nil
+		}
+	})
+}
+
+// scanFile scans the regular file or archive at src, writing its sanitized
+// copy to dst unless dst is empty.
+func (s *scanner) scanFile(src, dst string, perm fs.FileMode) error {
+	switch kind := archiveKindOf(src); kind {
+	case tarArchive, tarGzArchive:
+		return s.scanTar(src, dst, kind == tarGzArchive)
+	case zipArchive:
+		return s.scanZip(src, dst)
+	}
+
+	data, err := os.ReadFile(src) // #nosec G304 -- the path is provided by the user
+	if err != nil {
+		return err
+	}
+
+	data, err = s.sanitize(src, data)
+	if err != nil || dst == "" {
+		return err
+	}
+	return os.WriteFile(dst, data, perm)
+}
+
+// scanTar scans the tar archive at src, writing a sanitized archive to dst
+// unless dst is empty.
+func (s *scanner) scanTar(src, dst string, gzipped bool) error {
+	in, err := os.Open(src) // #nosec G304 -- the path is provided by the user
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var r io.Reader = in
+	if gzipped {
+		gz, err := gzip.NewReader(in)
+		if err != nil {
+			return fmt.Errorf("%s: %w", src, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	if dst == "" {
+		return s.copyTar(src, tar.NewReader(r), nil)
+	}
+
+	out, err := os.Create(dst) // #nosec G304 -- the path is provided by the user
+	if err != nil {
+		return err
+	}
+
+	// closed in order, flushing each writer into the next
+	closers := []io.Closer{out}
+	var w io.Writer = out
+	if gzipped {
+		gw := gzip.NewWriter(out)
+		closers = append([]io.Closer{gw}, closers...)
+		w = gw
+	}
+	tw := tar.NewWriter(w)
+	closers = append([]io.Closer{tw}, closers...)
+
+	err = s.copyTar(src, tar.NewReader(r), tw)
+	return closeOutput(dst, err, closers...)
+}
+
+// copyTar sanitizes the entries of the tar archive named name read from tr,
+// writing them to tw unless tw is nil.
+func (s *scanner) copyTar(name string, tr *tar.Reader, tw *tar.Writer) error {
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		var data []byte
+		if hdr.Typeflag == tar.TypeReg {
+			if data, err = io.ReadAll(tr); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if data, err = s.sanitize(path.Join(filepath.ToSlash(name), hdr.Name), data); err != nil {
+				return err
+			}
+			hdr.Size = int64(len(data))
+		}
+
+		if tw == nil {
+			continue
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+}
+
+// scanZip scans the zip archive at src, writing a sanitized archive to dst
+// unless dst is empty.
+func (s *scanner) scanZip(src, dst string) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+	defer zr.Close()
+
+	if dst == "" {
+		return s.copyZip(src, &zr.Reader, nil)
+	}
+
+	out, err := os.Create(dst) // #nosec G304 -- the path is provided by the user
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(out)
+
+	err = s.copyZip(src, &zr.Reader, zw)
+	return closeOutput(dst, err, zw, out)
+}
+
+// copyZip sanitizes the entries of the zip archive named name read from zr,
+// writing them to zw unless zw is nil.
+func (s *scanner) copyZip(name string, zr *zip.Reader, zw *zip.Writer) error {
+	for _, f := range zr.File {
+		var data []byte
+		if !f.FileInfo().IsDir() {
+			rc, err := f.Open()
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			data, err = io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if data, err = s.sanitize(path.Join(filepath.ToSlash(name), f.Name), data); err != nil {
+				return err
+			}
+		}
+
+		if zw == nil {
+			continue
+		}
+		hdr := f.FileHeader
+		w, err := zw.CreateHeader(&hdr)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sanitize returns the redacted data of the named file, or the data itself
+// if the file is binary, and adds the file to the summary.
+func (s *scanner) sanitize(name string, data []byte) ([]byte, error) {
+	s.files++
+
+	if isBinary(data) {
+		s.binaries++
+		fmt.Fprintf(s.summary, "%s: skipped (binary)\n", name)
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	redacted, err := s.p.redact(bytes.NewReader(data), &buf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	if redacted > 0 {
+		s.withFindings++
+		switch {
+		case s.p.whole:
+			fmt.Fprintf(s.summary, "%s: findings\n", name)
+		case redacted == 1:
+			fmt.Fprintf(s.summary, "%s: 1 line with findings\n", name)
+		default:
+			fmt.Fprintf(s.summary, "%s: %d lines with findings\n", name, redacted)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// isInside reports whether the path name is the directory dir or lies
+// inside it.
+func isInside(name, dir string) (bool, error) {
+	name, err := filepath.Abs(name)
+	if err != nil {
+		return false, err
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+
+	rel, err := filepath.Rel(dir, name)
+	if err != nil {
+		return false, nil // e.g. on different Windows volumes
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)), nil
+}
+
+// closeOutput closes the writers of the output file named name in order,
+// removing the file if err or any close error is not nil.
+func closeOutput(name string, err error, closers ...io.Closer) error {
+	for _, c := range closers {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	if err != nil {
+		os.Remove(name)
+	}
+	return err
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// bundleFiles are the files written to test directories and archives.
+var bundleFiles = map[string]string{
+	"logs/app.log":   "user=bob\ntoken=s3cr3t\nretry token=s3cr3t\n",
+	"logs/clean.log": "nothing to see\n",
+	"bin/tool":       "\x7fELF\x00token=s3cr3t",
+}
+
+// sanitizedFiles are the bundleFiles after sanitizing.
+var sanitizedFiles = map[string]string{
+	"logs/app.log":   "user=bob\ntoken=[redacted]\nretry token=[redacted]\n",
+	"logs/clean.log": "nothing to see\n",
+	"bin/tool":       "\x7fELF\x00token=s3cr3t",
+}
+
+func writeDir(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files[filepath.ToSlash(rel)] = string(b)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func sortedNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, name := range sortedNames(files) {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTarGz(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = string(b)
+	}
+}
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range sortedNames(files) {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readZip(t *testing.T, path string) map[string]string {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(b)
+	}
+	return files
+}
+
+func TestIsBinary(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected bool
+	}{
+		{"", false},
+		{"plain text\n", false},
+		{"héllo wörld", false},
+		{"\x7fELF\x00\x01", true},
+		{strings.Repeat("a", sniffLen) + "\x00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("len=%d", len(tt.input)), func(t *testing.T) {
+			actual := isBinary([]byte(tt.input))
+			if actual != tt.expected {
+				t.Errorf("Expected '%t', but got '%t'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestArchiveKindOf(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected archiveKind
+	}{
+		{"bundle.tar.gz", tarGzArchive},
+		{"BUNDLE.TGZ", tarGzArchive},
+		{"bundle.tar", tarArchive},
+		{"bundle.zip", zipArchive},
+		{"bundle.gz", notArchive},
+		{"app.log", notArchive},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual := archiveKindOf(tt.input)
+			if actual != tt.expected {
+				t.Errorf("Expected '%d', but got '%d'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	writeDir(t, filepath.Join(dir, "bundle"), bundleFiles)
+	writeTarGz(t, filepath.Join(dir, "bundle", "nested.tar.gz"), bundleFiles)
+	writeZip(t, filepath.Join(dir, "bundle.zip"), bundleFiles)
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"scan", "-regex", "s3cr3t",
+		filepath.Join(dir, "bundle"), filepath.Join(dir, "bundle.zip")}, nil, &stdout, &stderr)
+	if status != exitRedacted {
+		t.Errorf("Expected status %d, but got %d (stderr: %s)", exitRedacted, status, stderr.String())
+	}
+
+	expected := []string{
+		filepath.Join(dir, "bundle", "bin", "tool") + ": skipped (binary)",
+		filepath.Join(dir, "bundle", "logs", "app.log") + ": 2 lines with findings",
+		filepath.ToSlash(filepath.Join(dir, "bundle", "nested.tar.gz")) + "/bin/tool: skipped (binary)",
+		filepath.ToSlash(filepath.Join(dir, "bundle", "nested.tar.gz")) + "/logs/app.log: 2 lines with findings",
+		filepath.ToSlash(filepath.Join(dir, "bundle.zip")) + "/bin/tool: skipped (binary)",
+		filepath.ToSlash(filepath.Join(dir, "bundle.zip")) + "/logs/app.log: 2 lines with findings",
+		"9 files scanned, 3 with findings, 3 binary skipped",
+	}
+	actual := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected '%q', but got '%q'", expected, actual)
+	}
+
+	// the scanned files are left untouched
+	if files := readDir(t, filepath.Join(dir, "bundle")); files["logs/app.log"] != bundleFiles["logs/app.log"] {
+		t.Errorf("Expected '%s', but got '%s'", bundleFiles["logs/app.log"], files["logs/app.log"])
+	}
+}
+
+func TestScan_clean(t *testing.T) {
+	dir := t.TempDir()
+	writeDir(t, dir, bundleFiles)
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"scan", "-substring", "password", dir}, nil, &stdout, &stderr)
+	if status != exitOK {
+		t.Errorf("Expected status %d, but got %d (stderr: %s)", exitOK, status, stderr.String())
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	dir := t.TempDir()
+	writeDir(t, filepath.Join(dir, "bundle"), bundleFiles)
+	writeTarGz(t, filepath.Join(dir, "bundle.tar.gz"), bundleFiles)
+	writeZip(t, filepath.Join(dir, "bundle.zip"), bundleFiles)
+
+	var tests = []struct {
+		input string
+		read  func(t *testing.T, path string) map[string]string
+	}{
+		{"bundle", readDir},
+		{"bundle.tar.gz", readTarGz},
+		{"bundle.zip", readZip},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "sanitized-"+tt.input)
+
+			var stdout, stderr bytes.Buffer
+			status := run([]string{"sanitize", "-regex", "s3cr3t", "-o", output,
+				filepath.Join(dir, tt.input)}, nil, &stdout, &stderr)
+			if status != exitOK {
+				t.Fatalf("Expected status %d, but got %d (stderr: %s)", exitOK, status, stderr.String())
+			}
+
+			actual := tt.read(t, output)
+			if !reflect.DeepEqual(actual, sanitizedFiles) {
+				t.Errorf("Expected '%q', but got '%q'", sanitizedFiles, actual)
+			}
+		})
+	}
+}
+
+func TestSanitize_severalPaths(t *testing.T) {
+	dir := t.TempDir()
+	writeDir(t, filepath.Join(dir, "bundle"), bundleFiles)
+	writeZip(t, filepath.Join(dir, "bundle.zip"), bundleFiles)
+	output := filepath.Join(t.TempDir(), "out")
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"sanitize", "-regex", "s3cr3t", "-o", output,
+		filepath.Join(dir, "bundle"), filepath.Join(dir, "bundle.zip")}, nil, &stdout, &stderr)
+	if status != exitOK {
+		t.Fatalf("Expected status %d, but got %d (stderr: %s)", exitOK, status, stderr.String())
+	}
+
+	if actual := readDir(t, filepath.Join(output, "bundle")); !reflect.DeepEqual(actual, sanitizedFiles) {
+		t.Errorf("Expected '%q', but got '%q'", sanitizedFiles, actual)
+	}
+	if actual := readZip(t, filepath.Join(output, "bundle.zip")); !reflect.DeepEqual(actual, sanitizedFiles) {
+		t.Errorf("Expected '%q', but got '%q'", sanitizedFiles, actual)
+	}
+}
+
+func TestSanitize_errors(t *testing.T) {
+	dir := t.TempDir()
+	writeDir(t, dir, bundleFiles)
+	existing := filepath.Join(dir, "logs")
+
+	var tests = []struct {
+		args   []string
+		errMsg string
+	}{
+		{[]string{"sanitize", "-substring", "x", dir}, errOutputRequired.Error()},
+		{[]string{"sanitize", "-substring", "x", "-o", existing, dir}, fmt.Sprintf(errMsgFmtOutputExists, existing)},
+		{[]string{"sanitize", "-substring", "x", "-o", filepath.Join(dir, "out"), dir},
+			fmt.Sprintf(errMsgFmtOutputInsideSource, filepath.Join(dir, "out"), dir)},
+		{[]string{"sanitize", "-substring", "x", "-o", filepath.Join(dir, "out"), dir, existing},
+			fmt.Sprintf(errMsgFmtOutputInsideSource, filepath.Join(dir, "out", filepath.Base(dir)), dir)},
+		{[]string{"scan", "-substring", "x"}, errNoPaths.Error()},
+		{[]string{"scan", dir}, errNoRules.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("args=%q", tt.args), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, nil, &stdout, &stderr)
+			if status != exitError {
+				t.Errorf("Expected status %d, but got %d", exitError, status)
+			}
+			if !strings.Contains(stderr.String(), tt.errMsg) {
+				t.Errorf("Expected error '%s', but got '%s'", tt.errMsg, stderr.String())
+			}
+		})
+	}
+}
+
+func TestSanitize_removesPartialOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeDir(t, dir, bundleFiles)
+	writeDir(t, dir, map[string]string{"broken.zip": "not a zip archive"})
+
+	for _, paths := range [][]string{{dir}, {dir, filepath.Join(dir, "logs")}} {
+		t.Run(fmt.Sprintf("paths=%q", paths), func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "out")
+
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"sanitize", "-substring", "s3cr3t", "-o", output}, paths...)
+			if status := run(args, nil, &stdout, &stderr); status != exitError {
+				t.Fatalf("Expected status %d, but got %d", exitError, status)
+			}
+
+			if _, err := os.Lstat(output); !os.IsNotExist(err) {
+				t.Errorf("Expected %s to be removed, but got '%v'", output, err)
+			}
+		})
+	}
+}
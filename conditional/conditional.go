@@ -0,0 +1,125 @@
+package conditional
+
+import (
+	"fmt"
+
+	"github.com/kristinjeanna/redact"
+)
+
+const (
+	errMsgFmtWhenFailure       = "conditional.WhenRedactor.Redact: error while redacting, %w"
+	errMsgFmtSwitchFailure     = "conditional.SwitchRedactor.Redact: error while redacting, %w"
+	errMsgFmtFirstMatchFailure = "conditional.FirstMatchRedactor.Redact: error while redacting, %w"
+)
+
+// WhenRedactor is a redactor that redacts an input string only when it
+// satisfies a predicate, and otherwise returns it unchanged.
+type WhenRedactor struct {
+	predicate Predicate
+	redactor  redact.Redactor
+}
+
+// When returns a new WhenRedactor. A nil predicate is never satisfied.
+func When(predicate Predicate, redactor redact.Redactor) redact.Redactor {
+	return WhenRedactor{predicate: predicate, redactor: redactor}
+}
+
+// Redact invokes the redactor on s if s satisfies the predicate.
+func (r WhenRedactor) Redact(s string) (string, error) {
+	if r.predicate == nil || r.redactor == nil || !r.predicate(s) {
+		return s, nil
+	}
+
+	out, err := r.redactor.Redact(s)
+	if err != nil {
+		return "", fmt.Errorf(errMsgFmtWhenFailure, err)
+	}
+	return out, nil
+}
+
+// String returns a text representation of the redactor.
+func (r WhenRedactor) String() string {
+	return fmt.Sprintf("{when=%v}", r.redactor)
+}
+
+// Case associates a predicate with the redactor that a SwitchRedactor
+// invokes for input strings satisfying it.
+type Case struct {
+	When     Predicate
+	Redactor redact.Redactor
+}
+
+// SwitchRedactor is a redactor that invokes the redactor of the first case
+// whose predicate the input string satisfies, or a default redactor if there
+// is no such case.
+type SwitchRedactor struct {
+	cases           []Case
+	defaultRedactor redact.Redactor
+}
+
+// Switch returns a new SwitchRedactor. If the default redactor is nil,
+// input strings not satisfying any case are returned unchanged.
+func Switch(defaultRedactor redact.Redactor, cases ...Case) redact.Redactor {
+	return SwitchRedactor{cases: cases, defaultRedactor: defaultRedactor}
+}
+
+// Redact invokes the redactor of the first case that s satisfies.
+func (r SwitchRedactor) Redact(s string) (string, error) {
+	redactor := r.defaultRedactor
+	for _, c := range r.cases {
+		if c.When != nil && c.When(s) {
+			redactor = c.Redactor
+			break
+		}
+	}
+
+	if redactor == nil {
+		return s, nil
+	}
+
+	out, err := redactor.Redact(s)
+	if err != nil {
+		return "", fmt.Errorf(errMsgFmtSwitchFailure, err)
+	}
+	return out, nil
+}
+
+// String returns a text representation of the redactor.
+func (r SwitchRedactor) String() string {
+	redactors := make([]redact.Redactor, 0, len(r.cases))
+	for _, c := range r.cases {
+		redactors = append(redactors, c.Redactor)
+	}
+	return fmt.Sprintf("{cases=%v; default=%v}", redactors, r.defaultRedactor)
+}
+
+// FirstMatchRedactor is a redactor that invokes a sequence of redactors on
+// the input string and returns the output of the first redactor that
+// changes it.
+type FirstMatchRedactor []redact.Redactor
+
+// FirstMatch returns a new FirstMatchRedactor.
+func FirstMatch(redactors ...redact.Redactor) redact.Redactor {
+	return FirstMatchRedactor(redactors)
+}
+
+// Redact returns the output of the first redactor that changes s, or s if
+// none of them does.
+func (r FirstMatchRedactor) Redact(s string) (string, error) {
+	for _, redactor := range r {
+		out, err := redactor.Redact(s)
+		if err != nil {
+			return "", fmt.Errorf(errMsgFmtFirstMatchFailure, err)
+		}
+		if out != s {
+			return out, nil
+		}
+	}
+
+	return s, nil
+}
+
+// String returns a text representation of the redactor.
+func (r FirstMatchRedactor) String() string {
+	return fmt.Sprintf("{firstMatch=%v}", []redact.Redactor(r))
+}
@@ -0,0 +1,6 @@
+// Package conditional provides redactors that route an input string to other
+// redactors depending on its content: the WhenRedactor, the SwitchRedactor
+// and the FirstMatchRedactor, along with predicates for selecting inputs. The
+// ExceptRedactor uses the same predicates as an allow-list of text that is
+// never redacted.
+package conditional
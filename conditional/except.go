@@ -0,0 +1,117 @@
+package conditional
+
+import (
+	"fmt"
+
+	"github.com/kristinjeanna/redact"
+)
+
+const errMsgFmtExceptFailure = "conditional.ExceptRedactor.Redact: error while redacting, %w"
+
+// ExceptRedactor is a redactor that leaves the text satisfying any of a set
+// of allow-list predicates unchanged. If the underlying redactor implements
+// redact.Detector, the predicates are checked against the text of each
+// detected span, so that only allowed matches come through unchanged while
+// the other detected spans are replaced.
+// Otherwise, they are checked against the entire input string.
+type ExceptRedactor struct {
+	redactor redact.Redactor
+	allowed  []Predicate
+}
+
+// exceptDetector is an ExceptRedactor for an underlying redactor
+// implementing redact.Detector.
+type exceptDetector struct {
+	ExceptRedactor
+}
+
+// Except returns a new ExceptRedactor for the redactor and the allow-list
+// predicates, such as Equals for exact strings, MatchesFully for regexes or
+// any other Predicate. If the redactor implements redact.Detector, so does
+// the returned redactor.
+//
+// For regex pairs, use regex.Pair.Except instead, which also preserves the
+// sequential application of pairs by a regex.RegexRedactor.
+func Except(redactor redact.Redactor, allowed ...Predicate) redact.Redactor {
+	r := ExceptRedactor{redactor: redactor, allowed: allowed}
+	if _, ok := redactor.(redact.Detector); ok {
+		return exceptDetector{r}
+	}
+	return r
+}
+
+// Redact invokes the redactor on s unless s satisfies an allow-list
+// predicate.
+func (r ExceptRedactor) Redact(s string) (string, error) {
+	if r.isAllowed(s) {
+		return s, nil
+	}
+
+	return r.redact(s)
+}
+
+// redact invokes the redactor on s.
+func (r ExceptRedactor) redact(s string) (string, error) {
+	out, err := r.redactor.Redact(s)
+	if err != nil {
+		return "", fmt.Errorf(errMsgFmtExceptFailure, err)
+	}
+	return out, nil
+}
+
+// String returns a text representation of the redactor.
+func (r ExceptRedactor) String() string {
+	return fmt.Sprintf("{except=%v}", r.redactor)
+}
+
+// isAllowed reports whether s satisfies any of the allow-list predicates.
+func (r ExceptRedactor) isAllowed(s string) bool {
+	for _, p := range r.allowed {
+		if p != nil && p(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact replaces the detected spans of s that do not satisfy an allow-list
+// predicate, leaving the text of the allowed spans and the rest of s
+// unchanged. Since the spans are detected in s as a whole, anchors and word
+// boundaries next to an allowed span match as they would without it. When
+// no detected span is allowed, it is the same as invoking the redactor on s.
+func (r exceptDetector) Redact(s string) (string, error) {
+	spans, err := r.redactor.(redact.Detector).Detect(s)
+	if err != nil {
+		return "", fmt.Errorf(errMsgFmtExceptFailure, err)
+	}
+
+	kept := r.keep(s, spans)
+	if len(kept) == len(spans) {
+		return r.redact(s)
+	}
+
+	return redact.ApplySpans(s, kept), nil
+}
+
+// Detect returns the spans detected by the redactor whose text does not
+// satisfy any of the allow-list predicates.
+func (r exceptDetector) Detect(s string) ([]redact.Span, error) {
+	spans, err := r.redactor.(redact.Detector).Detect(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.keep(s, spans), nil
+}
+
+// keep returns a new slice of the spans whose text in s does not satisfy
+// any of the allow-list predicates.
+func (r exceptDetector) keep(s string, spans []redact.Span) []redact.Span {
+	kept := make([]redact.Span, 0, len(spans))
+	for _, span := range spans {
+		if !r.isAllowed(s[span.Start:span.End]) {
+			kept = append(kept, span)
+		}
+	}
+	return kept
+}
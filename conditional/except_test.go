@@ -0,0 +1,158 @@
+package conditional
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/metrics"
+	"github.com/kristinjeanna/redact/regex"
+	"github.com/kristinjeanna/redact/simple"
+	"github.com/kristinjeanna/redact/url"
+)
+
+func TestExcept_detector(t *testing.T) {
+	ipPair, _ := regex.NewPairUsingSimple("[ip]", `\d+\.\d+\.\d+\.\d+`)
+	emailPair, _ := regex.NewPairUsingSimple("[email]", `[\w.]+@[\w.]+`)
+	regexRedactor, _ := regex.New([]regex.Pair{*ipPair, *emailPair})
+
+	r := Except(regexRedactor,
+		Equals("127.0.0.1"),
+		MatchesFully(regexp.MustCompile(`noreply@.*`)),
+		func(s string) bool { return s == "4111111111111111" },
+	)
+	if _, ok := r.(redact.Detector); !ok {
+		t.Fatal("Expected a redact.Detector")
+	}
+
+	runCases(t, r, []testCase{
+		{"", ""},
+		{"from 127.0.0.1 to 10.0.0.1", "from 127.0.0.1 to [ip]"},
+		{"noreply@ourco.com cc bob@ourco.com", "noreply@ourco.com cc [email]"},
+		{"bob.noreply@ourco.com", "[email]"},
+		{"127.0.0.1", "127.0.0.1"},
+	})
+}
+
+func TestExcept_preservesRedact(t *testing.T) {
+	counters := metrics.NewCounters()
+	first, _ := regex.NewPairUsingSimple("X", `a`)
+	second, _ := regex.NewPairUsingSimple("Y", `X`)
+	regexRedactor, _ := regex.NewFromOptions(
+		[]regex.Pair{first.Named("first"), second.Named("second")},
+		regex.WithHook(counters),
+	)
+
+	r := Except(regexRedactor, Equals("zzz"))
+
+	// with no allow-list hits, the output is that of the wrapped redactor,
+	// whose pairs apply sequentially
+	runCases(t, r, []testCase{
+		{"aaa", "YYY"},
+		{"a zzz", "Y zzz"},
+		{"", ""},
+		{"no match", "no mYtch"},
+	})
+
+	if got := counters.Get("second").Matches; got != 5 {
+		t.Errorf("Expected '%d', but got '%d'", 5, got)
+	}
+
+	// around an allowed span, the other detected spans are replaced
+	allowX := Except(regexRedactor, Equals("a"))
+	runCases(t, allowX, []testCase{
+		{"aaa", "aaa"},
+		{"a X a", "a Y a"},
+	})
+}
+
+func TestExcept_boundariesNextToAllowed(t *testing.T) {
+	wordPair, _ := regex.NewPairUsingSimple("[w]", `[a-z]+`)
+	numberPair, _ := regex.NewPairUsingSimple("[n]", `\b\d+\b`)
+	regexRedactor, _ := regex.New([]regex.Pair{*wordPair, *numberPair})
+
+	r := Except(regexRedactor, Equals("abc"))
+
+	// the number pair sees the word boundaries of the whole input, not of
+	// the text around the allowed span
+	runCases(t, r, []testCase{
+		{"abc123", "abc123"},
+		{"123abc", "123abc"},
+		{"abc 123 xyz", "abc [n] [w]"},
+		{"xyz 42abc", "[w] 42abc"},
+	})
+}
+
+// fixedDetector is a detector returning the same spans for any input.
+type fixedDetector []redact.Span
+
+func (d fixedDetector) Redact(s string) (string, error) {
+	return redact.ApplySpans(s, d), nil
+}
+
+func (d fixedDetector) Detect(string) ([]redact.Span, error) {
+	return d, nil
+}
+
+func TestExcept_detectKeepsDetectorSpans(t *testing.T) {
+	detector := fixedDetector{
+		{Start: 0, End: 3, Replacement: "[a]"},
+		{Start: 4, End: 7, Replacement: "[b]"},
+	}
+	expected := fmt.Sprint(detector)
+
+	spans, err := Except(detector, Equals("abc")).(redact.Detector).Detect("abc xyz")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := "[{4 7 [b]}]"; fmt.Sprint(spans) != want {
+		t.Errorf("Expected '%s', but got '%v'", want, spans)
+	}
+	if actual := fmt.Sprint(detector); actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
+
+func TestExcept_redactor(t *testing.T) {
+	r := Except(simple.New("[redacted]"), Equals("public"), nil)
+	if _, ok := r.(redact.Detector); ok {
+		t.Fatal("Expected no redact.Detector")
+	}
+
+	runCases(t, r, []testCase{
+		{"public", "public"},
+		{"secret", "[redacted]"},
+		{"public secret", "[redacted]"},
+	})
+}
+
+func TestExcept_errors(t *testing.T) {
+	failingPair, _ := regex.NewPair(url.New("XXXXX", nil), `\w+`)
+	regexRedactor, _ := regex.New([]regex.Pair{*failingPair})
+
+	redactors := []redact.Redactor{
+		Except(url.New("XXXXX", nil)),
+		Except(regexRedactor),
+	}
+
+	for _, r := range redactors {
+		t.Run(fmt.Sprintf("redactor=%v; ", r), func(t *testing.T) {
+			_, err := r.Redact("not a url")
+			if err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+		})
+	}
+
+	if _, err := redactors[1].(redact.Detector).Detect("not a url"); err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+}
+
+func TestExcept_string(t *testing.T) {
+	expected := `{except={replacement="X"}}`
+	if got := Except(simple.New("X")).(fmt.Stringer).String(); got != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
@@ -0,0 +1,107 @@
+package conditional
+
+import (
+	pkgurl "net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Predicate reports whether an input string satisfies a condition.
+type Predicate func(s string) bool
+
+// MinLength returns a predicate reporting whether the input string is at
+// least n characters long.
+func MinLength(n int) Predicate {
+	return func(s string) bool {
+		return utf8.RuneCountInString(s) >= n
+	}
+}
+
+// MaxLength returns a predicate reporting whether the input string is at
+// most n characters long.
+func MaxLength(n int) Predicate {
+	return func(s string) bool {
+		return utf8.RuneCountInString(s) <= n
+	}
+}
+
+// HasPrefix returns a predicate reporting whether the input string begins
+// with the prefix.
+func HasPrefix(prefix string) Predicate {
+	return func(s string) bool {
+		return strings.HasPrefix(s, prefix)
+	}
+}
+
+// Matches returns a predicate reporting whether the input string contains a
+// match of the regex.
+func Matches(re *regexp.Regexp) Predicate {
+	return func(s string) bool {
+		return re.MatchString(s)
+	}
+}
+
+// MatchesFully returns a predicate reporting whether the entire input string
+// is a match of the regex.
+func MatchesFully(re *regexp.Regexp) Predicate {
+	anchored := regexp.MustCompile(`^(?:` + re.String() + `)$`)
+	return func(s string) bool {
+		return anchored.MatchString(s)
+	}
+}
+
+// Equals returns a predicate reporting whether the input string is equal to
+// any of the values.
+func Equals(values ...string) Predicate {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return func(s string) bool {
+		return set[s]
+	}
+}
+
+// IsURL returns a predicate reporting whether the input string parses as an
+// absolute URL, as accepted by the url package's URLRedactor.
+func IsURL() Predicate {
+	return func(s string) bool {
+		u, err := pkgurl.ParseRequestURI(s)
+		return err == nil && u.Scheme != ""
+	}
+}
+
+// Not returns a predicate reporting whether the input string does not
+// satisfy p.
+func Not(p Predicate) Predicate {
+	return func(s string) bool {
+		return !p(s)
+	}
+}
+
+// And returns a predicate reporting whether the input string satisfies all
+// of the predicates.
+func And(predicates ...Predicate) Predicate {
+	return func(s string) bool {
+		for _, p := range predicates {
+			if !p(s) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a predicate reporting whether the input string satisfies any
+// of the predicates.
+func Or(predicates ...Predicate) Predicate {
+	return func(s string) bool {
+		for _, p := range predicates {
+			if p(s) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,6 @@
+// Package confredact provides redactors for configuration files in YAML, INI
+// and Java properties formats. The redactors process files line by line,
+// tracking sections and nesting to determine the dotted path of each key,
+// such as "db.password". The values of the keys matching a set of patterns
+// are redacted, while comments, ordering and formatting are kept intact.
+package confredact
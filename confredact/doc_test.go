@@ -0,0 +1,33 @@
+package confredact
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func ExampleNewYAML() {
+	redactor, err := NewYAML(simple.New("[redacted]"))
+	if err != nil {
+		log.Fatalf("an error occurred while creating redactor: %s", err)
+	}
+
+	result, err := redactor.Redact(`server:
+  port: 8080
+db:
+  user: app
+  password: "s3cret" # rotated monthly
+`)
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+
+	fmt.Print(result)
+	// Output:
+	// server:
+	//   port: 8080
+	// db:
+	//   user: app
+	//   password: "[redacted]" # rotated monthly
+}
@@ -0,0 +1,133 @@
+package confredact
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+// INIRedactor is a redactor for INI files. Lines starting with ";" or "#"
+// are comments, "[section]" lines start sections, and other lines hold a
+// key and a value separated by "=" or ":". The path of a key consists of its
+// section, split at dots, and the key itself, so the password in
+//
+//	[db.primary]
+//	password = s3cret
+//
+// has the path "db.primary.password". Values may continue on the following
+// indented lines without separator, which are redacted along with the first
+// line. A value
+// enclosed in quotes keeps its quotes. Everything following the separator
+// is treated as the value, including any inline comment.
+type INIRedactor struct {
+	settings
+}
+
+// NewINI returns a new INIRedactor that replaces the values of the matching
+// keys with the output of the redactor.
+func NewINI(redactor redact.Redactor, opts ...Option) (redact.Redactor, error) {
+	s, err := newSettings(redactor, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return INIRedactor{settings: s}, nil
+}
+
+// Redact redacts the values of the matching keys in the INI file s.
+func (r INIRedactor) Redact(s string) (string, error) {
+	lines := strings.SplitAfter(s, "\n")
+	var b strings.Builder
+	var section []string
+	matched := false // whether the value of the last key is redacted
+
+	for _, line := range lines {
+		content, eol := splitLine(line)
+		trimmed := strings.TrimSpace(content)
+
+		switch {
+		case trimmed == "" || trimmed[0] == ';' || trimmed[0] == '#':
+			b.WriteString(line)
+			continue
+
+		case (content[0] == ' ' || content[0] == '\t') && !strings.ContainsAny(trimmed, "=:"):
+			// continuation of the previous value; indented lines with a
+			// separator hold keys, as in git config files
+			if !matched {
+				b.WriteString(line)
+				continue
+			}
+			indent := content[:len(content)-len(strings.TrimLeft(content, " \t"))]
+			out, err := r.redactValue(trimmed)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(indent + out + eol)
+			continue
+
+		case trimmed[0] == '[' && strings.HasSuffix(trimmed, "]"):
+			section = splitKey(sectionName(trimmed[1 : len(trimmed)-1]))
+			matched = false
+			b.WriteString(line)
+			continue
+		}
+
+		sep := strings.IndexAny(content, "=:")
+		if sep < 0 {
+			matched = false
+			b.WriteString(line)
+			continue
+		}
+
+		key := strings.TrimSpace(content[:sep])
+		keyPath := append(append([]string{}, section...), splitKey(key)...)
+		matched = r.matches(keyPath)
+
+		value := content[sep+1:]
+		trimmedValue := strings.TrimSpace(value)
+		if !matched || trimmedValue == "" {
+			b.WriteString(line)
+			continue
+		}
+
+		out, err := r.redactValue(trimmedValue)
+		if err != nil {
+			return "", err
+		}
+		lead := value[:strings.Index(value, trimmedValue)]
+		trail := value[len(lead)+len(trimmedValue):]
+		b.WriteString(content[:sep+1] + lead + out + trail + eol)
+	}
+
+	return b.String(), nil
+}
+
+// String returns a text representation of the redactor.
+func (r INIRedactor) String() string {
+	return fmt.Sprintf("{format=\"ini\"; %s}", r.settings)
+}
+
+// redactValue redacts the value, keeping its enclosing quotes, if any.
+func (r INIRedactor) redactValue(value string) (string, error) {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		out, err := r.redact(value[1 : len(value)-1])
+		if err != nil {
+			return "", err
+		}
+		return value[:1] + out + value[:1], nil
+	}
+
+	return r.redact(value)
+}
+
+// sectionName returns the dotted name of the section header contents,
+// converting subsections of the form `section "sub"` to "section.sub".
+func sectionName(header string) string {
+	name, sub, ok := strings.Cut(strings.TrimSpace(header), " ")
+	sub = strings.Trim(strings.TrimSpace(sub), `"`)
+	if !ok || sub == "" {
+		return name
+	}
+	return name + "." + sub
+}
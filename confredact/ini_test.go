@@ -0,0 +1,62 @@
+package confredact
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func TestINIRedactor(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"; comment\nname = app\npassword = s3cret\n", "; comment\nname = app\npassword = [redacted]\n"},
+		{"password=s3cret\r\n", "password=[redacted]\r\n"},
+		{"password: s3cret  \n", "password: [redacted]  \n"},
+		{"password = \"s3 cret\"\n", "password = \"[redacted]\"\n"},
+		{"password =\n", "password =\n"},
+		{"[db]\npassword = a\n[log]\nlevel = info\n", "[db]\npassword = [redacted]\n[log]\nlevel = info\n"},
+		{"[credentials]\nuser = bob\n", "[credentials]\nuser = [redacted]\n"},
+		{"[remote \"origin\"]\n\ttoken = abc\n\turl = x\n", "[remote \"origin\"]\n\ttoken = [redacted]\n\turl = x\n"},
+		{"secret = line1\n  line2\nname = x\n  more\n", "secret = [redacted]\n  [redacted]\nname = x\n  more\n"},
+		{"# password = x\n", "# password = x\n"},
+	}
+
+	redactor, err := NewINI(simple.New(replacement))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestINIRedactor_sectionKeys(t *testing.T) {
+	redactor, err := NewINI(simple.New(replacement), WithKeys("db.primary.user"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "user = a\n[db.primary]\nuser = b\n[db.replica]\nuser = c\n"
+	expected := "user = a\n[db.primary]\nuser = [redacted]\n[db.replica]\nuser = c\n"
+
+	actual, err := redactor.Redact(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
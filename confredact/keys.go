@@ -0,0 +1,155 @@
+package confredact
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+var (
+	errRedactorNil = errors.New("confredact: redactor must not be nil")
+	errKeysEmpty   = errors.New("confredact: at least one key pattern is required")
+
+	errMsgFmtBadPattern    = "confredact: invalid key pattern %q"
+	errMsgFmtRedactFailure = "confredact: error while redacting, %w"
+)
+
+// DefaultKeys returns the patterns of the keys whose values are redacted by
+// default.
+func DefaultKeys() []string {
+	return []string{
+		"*password*",
+		"*passwd*",
+		"*secret*",
+		"*token",
+		"*api_key",
+		"*api-key",
+		"*apikey",
+		"*private_key",
+		"*private-key",
+		"*credentials*",
+	}
+}
+
+// keyPattern is a key pattern split into its dot-separated segments.
+type keyPattern []string
+
+// matches reports whether the pattern matches the end of the key path,
+// segment by segment.
+func (p keyPattern) matches(keyPath []string) bool {
+	if len(p) > len(keyPath) {
+		return false
+	}
+
+	offset := len(keyPath) - len(p)
+	for i, seg := range p {
+		if ok, _ := path.Match(seg, keyPath[offset+i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// settings holds the settings shared by all redactors of the package.
+type settings struct {
+	redactor redact.Redactor
+	keys     []keyPattern
+}
+
+// newSettings returns the settings for the redactor with the provided
+// options.
+func newSettings(redactor redact.Redactor, opts []Option) (settings, error) {
+	if redactor == nil {
+		return settings{}, errRedactorNil
+	}
+
+	patterns := DefaultKeys()
+	for _, o := range opts {
+		o(&patterns)
+	}
+
+	if len(patterns) == 0 {
+		return settings{}, errKeysEmpty
+	}
+
+	s := settings{redactor: redactor}
+	for _, p := range patterns {
+		segments := splitKey(p)
+		for _, seg := range segments {
+			if _, err := path.Match(seg, ""); err != nil || seg == "" {
+				return settings{}, fmt.Errorf(errMsgFmtBadPattern, p)
+			}
+		}
+		s.keys = append(s.keys, segments)
+	}
+
+	return s, nil
+}
+
+// matches reports whether the key path, or the path of any of its
+// ancestors, matches a key pattern.
+func (s settings) matches(keyPath []string) bool {
+	for n := 1; n <= len(keyPath); n++ {
+		for _, p := range s.keys {
+			if p.matches(keyPath[:n]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redact returns the output of the redactor for the value.
+func (s settings) redact(value string) (string, error) {
+	out, err := s.redactor.Redact(value)
+	if err != nil {
+		return "", fmt.Errorf(errMsgFmtRedactFailure, err)
+	}
+	return out, nil
+}
+
+// String returns a text representation of the settings.
+func (s settings) String() string {
+	keys := make([]string, len(s.keys))
+	for i, k := range s.keys {
+		keys[i] = strings.Join(k, ".")
+	}
+	return fmt.Sprintf("keys=%q; redactor=%v", keys, s.redactor)
+}
+
+// splitKey splits a dotted key into its lower-case segments.
+func splitKey(key string) []string {
+	return strings.Split(strings.ToLower(key), ".")
+}
+
+// splitLine splits a line into its content and its line ending.
+func splitLine(line string) (string, string) {
+	content := strings.TrimRight(line, "\r\n")
+	return content, line[len(content):]
+}
+
+// countIndent returns the number of leading spaces of s.
+func countIndent(s string) int {
+	return len(s) - len(strings.TrimLeft(s, " "))
+}
+
+// Option defines options for creating new config file redactors.
+type Option func(*[]string)
+
+/*
+WithKeys sets the patterns of the keys whose values are redacted, replacing
+the default ones. A pattern consists of dot-separated segments, each
+following the syntax of path.Match, and matches the keys whose dotted paths
+end with matching segments, ignoring case. For example, "password" matches
+"password" and "db.password", and "db.*" matches all keys nested in any "db"
+key. The values of all keys nested in a matching key are redacted too.
+Default is the patterns returned by DefaultKeys.
+*/
+func WithKeys(patterns ...string) Option {
+	return func(p *[]string) {
+		*p = patterns
+	}
+}
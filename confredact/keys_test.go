@@ -0,0 +1,103 @@
+package confredact
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/internal/redacttest"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func TestKeyPattern_matches(t *testing.T) {
+	var tests = []struct {
+		pattern  string
+		key      string
+		expected bool
+	}{
+		{"password", "password", true},
+		{"password", "db.password", true},
+		{"password", "password.hint", false},
+		{"db.password", "prod.db.password", true},
+		{"db.password", "password", false},
+		{"*password*", "db.admin_password_file", true},
+		{"db.*", "db.user", true},
+		{"DB.User", "db.user", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("pattern=%q;key=%q", tt.pattern, tt.key), func(t *testing.T) {
+			actual := keyPattern(splitKey(tt.pattern)).matches(splitKey(tt.key))
+			if actual != tt.expected {
+				t.Errorf("Expected '%t', but got '%t'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNew_errors(t *testing.T) {
+	constructors := map[string]func(redact.Redactor, ...Option) (redact.Redactor, error){
+		"yaml":       NewYAML,
+		"ini":        NewINI,
+		"properties": NewProperties,
+	}
+
+	var tests = []struct {
+		redactor redact.Redactor
+		opts     []Option
+		expected string
+	}{
+		{nil, nil, errRedactorNil.Error()},
+		{simple.New(replacement), []Option{WithKeys()}, errKeysEmpty.Error()},
+		{simple.New(replacement), []Option{WithKeys("[")}, fmt.Sprintf(errMsgFmtBadPattern, "[")},
+		{simple.New(replacement), []Option{WithKeys("db..password")}, fmt.Sprintf(errMsgFmtBadPattern, "db..password")},
+	}
+
+	for name, newRedactor := range constructors {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("format=%s;expected=%q", name, tt.expected), func(t *testing.T) {
+				_, err := newRedactor(tt.redactor, tt.opts...)
+				if err == nil || err.Error() != tt.expected {
+					t.Errorf("Expected error '%s', but got '%v'", tt.expected, err)
+				}
+			})
+		}
+	}
+}
+
+func TestRedact_errors(t *testing.T) {
+	var tests = []struct {
+		newRedactor func(redact.Redactor, ...Option) (redact.Redactor, error)
+		input       string
+	}{
+		{NewYAML, "password: x\n"},
+		{NewYAML, "password: |\n  x\n"},
+		{NewINI, "password = x\n"},
+		{NewProperties, "password = x\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			redactor, err := tt.newRedactor(redacttest.Failing{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := redactor.Redact(tt.input); !errors.Is(err, redacttest.ErrFailed) {
+				t.Errorf("Expected '%v', but got '%v'", redacttest.ErrFailed, err)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	redactor, err := NewYAML(simple.New(replacement), WithKeys("DB.Password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{format="yaml"; keys=["db.password"]; redactor={replacement="[redacted]"}}`
+	if fmt.Sprint(redactor) != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, fmt.Sprint(redactor))
+	}
+}
@@ -0,0 +1,164 @@
+package confredact
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+// PropertiesRedactor is a redactor for Java properties files. Lines
+// starting with "#" or "!" are comments, and other lines hold a key and a
+// value separated by "=", ":" or whitespace. Keys are typically dotted, as
+// in "spring.datasource.password", which is their path. A value may
+// continue on the following lines when its line ends with a backslash; a
+// redacted value is written on the first of its lines, and its continuation
+// lines are removed. The redactor receives values with escape sequences
+// other than \uXXXX decoded, and its output is escaped as needed.
+type PropertiesRedactor struct {
+	settings
+}
+
+// NewProperties returns a new PropertiesRedactor that replaces the values
+// of the matching keys with the output of the redactor.
+func NewProperties(redactor redact.Redactor, opts ...Option) (redact.Redactor, error) {
+	s, err := newSettings(redactor, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return PropertiesRedactor{settings: s}, nil
+}
+
+// Redact redacts the values of the matching keys in the properties file s.
+func (r PropertiesRedactor) Redact(s string) (string, error) {
+	lines := strings.SplitAfter(s, "\n")
+	var b strings.Builder
+
+	for i := 0; i < len(lines); i++ {
+		content, eol := splitLine(lines[i])
+		trimmed := strings.TrimLeft(content, " \t\f")
+
+		if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+			b.WriteString(lines[i])
+			continue
+		}
+
+		// join the continuation lines of the logical line
+		last := i
+		logical := trimmed
+		for endsWithEscape(logical) && last+1 < len(lines) {
+			last++
+			next, _ := splitLine(lines[last])
+			logical = logical[:len(logical)-1] + strings.TrimLeft(next, " \t\f")
+		}
+
+		key, valueStart := parsePropertiesKey(logical)
+		value := unescapeProperties(logical[valueStart:])
+		if value == "" || !r.matches(splitKey(key)) {
+			for _, line := range lines[i : last+1] {
+				b.WriteString(line)
+			}
+			i = last
+			continue
+		}
+
+		out, err := r.redact(value)
+		if err != nil {
+			return "", err
+		}
+
+		indent := content[:len(content)-len(trimmed)]
+		_, eol = splitLine(lines[last])
+		b.WriteString(indent + logical[:valueStart] + escapeProperties(out) + eol)
+		i = last
+	}
+
+	return b.String(), nil
+}
+
+// String returns a text representation of the redactor.
+func (r PropertiesRedactor) String() string {
+	return fmt.Sprintf("{format=\"properties\"; %s}", r.settings)
+}
+
+// parsePropertiesKey returns the unescaped key of the logical line s and
+// the offset of its value.
+func parsePropertiesKey(s string) (string, int) {
+	var key strings.Builder
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) {
+			i++
+			key.WriteByte(s[i])
+			continue
+		}
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			break
+		}
+		key.WriteByte(c)
+	}
+
+	// separator: whitespace, optionally followed by "=" or ":" and
+	// whitespace
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\f') {
+		i++
+	}
+	if i < len(s) && (s[i] == '=' || s[i] == ':') {
+		i++
+		for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\f') {
+			i++
+		}
+	}
+
+	return key.String(), i
+}
+
+// endsWithEscape reports whether s ends with an odd number of backslashes.
+func endsWithEscape(s string) bool {
+	n := len(s) - len(strings.TrimRight(s, `\`))
+	return n%2 == 1
+}
+
+// unescapeProperties decodes the escape sequences of a value, except
+// \uXXXX, which is kept as is.
+func unescapeProperties(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			b.WriteString(`\u`)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// escapeProperties escapes a value so that it is read back unchanged.
+func escapeProperties(s string) string {
+	out := strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`, "\f", `\f`).Replace(s)
+	if strings.HasPrefix(out, " ") {
+		out = `\` + out
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package confredact
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kristinjeanna/redact/internal/redacttest"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func TestPropertiesRedactor(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"# comment\n! comment\napp.name=demo\n", "# comment\n! comment\napp.name=demo\n"},
+		{"spring.datasource.password=s3cret\n", "spring.datasource.password=[redacted]\n"},
+		{"db.password : s3cret\r\n", "db.password : [redacted]\r\n"},
+		{"db.password s3cret\n", "db.password [redacted]\n"},
+		{"  db.password = s3cret\n", "  db.password = [redacted]\n"},
+		{"db.password=\n", "db.password=\n"},
+		{"db\\.password=s3cret\n", "db\\.password=[redacted]\n"},
+		{"api.token=abc\\\n    def\\\n    ghi\nname=x\n", "api.token=[redacted]\nname=x\n"},
+		{"app.list=a,\\\n  b\napp.password=x", "app.list=a,\\\n  b\napp.password=[redacted]"},
+	}
+
+	redactor, err := NewProperties(simple.New(replacement))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPropertiesRedactor_escaping(t *testing.T) {
+	var seen string
+	recorder := redacttest.Func(func(s string) (string, error) {
+		seen = s
+		return " a\\b\n", nil
+	})
+
+	redactor, err := NewProperties(recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := redactor.Redact("password=x\\ty\\=z\\u0041\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if expected := "x\ty=z\\u0041"; seen != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, seen)
+	}
+	if expected := "password=\\ a\\\\b\\n\n"; actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
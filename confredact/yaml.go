@@ -0,0 +1,350 @@
+package confredact
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+// YAMLRedactor is a redactor for YAML documents. It supports block
+// mappings and sequences, with plain, quoted and block scalars as values;
+// flow collections are redacted as a whole when their key matches. The
+// path of a key consists of the keys of its enclosing mappings, ignoring
+// sequences, so the password in
+//
+//	db:
+//	  replicas:
+//	    - host: a
+//	      password: s3cret
+//
+// has the path "db.replicas.password". Redacted values keep their quoting,
+// and plain values are quoted when the output would not be read back as a
+// plain scalar. A redacted multi-line scalar is written on a single line,
+// and a redacted block scalar is replaced by the lines of the output.
+type YAMLRedactor struct {
+	settings
+}
+
+// NewYAML returns a new YAMLRedactor that replaces the values of the
+// matching keys with the output of the redactor.
+func NewYAML(redactor redact.Redactor, opts ...Option) (redact.Redactor, error) {
+	s, err := newSettings(redactor, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return YAMLRedactor{settings: s}, nil
+}
+
+// yamlKey is a mapping key on the path to the current line.
+type yamlKey struct {
+	indent   int
+	segments []string
+}
+
+// Redact redacts the values of the matching keys in the YAML document s.
+func (r YAMLRedactor) Redact(s string) (string, error) {
+	lines := strings.SplitAfter(s, "\n")
+	var b strings.Builder
+	var stack []yamlKey
+
+	for i := 0; i < len(lines); i++ {
+		content, eol := splitLine(lines[i])
+		indent := countIndent(content)
+		rest := content[indent:]
+
+		if rest == "" || rest[0] == '#' || (indent == 0 && (strings.HasPrefix(rest, "---") || strings.HasPrefix(rest, "..."))) {
+			b.WriteString(lines[i])
+			continue
+		}
+
+		// sequence entries start with "- ", possibly repeatedly
+		col, isItem := indent, false
+		for rest == "-" || strings.HasPrefix(rest, "- ") {
+			n := 1 + countIndent(rest[1:])
+			col, rest, isItem = col+n, rest[n:], true
+		}
+
+		for len(stack) > 0 && (stack[len(stack)-1].indent > indent ||
+			(!isItem && stack[len(stack)-1].indent >= col)) {
+			stack = stack[:len(stack)-1]
+		}
+
+		prefix := content[:col]
+		value := rest
+		if key, valueStart, ok := parseYAMLKey(rest); ok {
+			stack = append(stack, yamlKey{indent: col, segments: splitKey(key)})
+			prefix, value = content[:col+valueStart], rest[valueStart:]
+		} else if !isItem {
+			b.WriteString(lines[i]) // unsupported construct
+			continue
+		}
+
+		matched := r.matches(yamlPath(stack))
+		trimmed := strings.TrimLeft(value, " ")
+
+		if strings.HasPrefix(trimmed, "|") || strings.HasPrefix(trimmed, ">") {
+			b.WriteString(lines[i])
+			end, err := r.blockScalar(&b, lines, i+1, indent, matched)
+			if err != nil {
+				return "", err
+			}
+			i = end - 1
+			continue
+		}
+
+		if !matched || trimmed == "" || trimmed[0] == '#' {
+			b.WriteString(lines[i])
+			continue
+		}
+
+		// fold the continuation lines of a multi-line scalar into one line
+		for i+1 < len(lines) {
+			next, nextEOL := splitLine(lines[i+1])
+			nextIndent := countIndent(next)
+			if nextIndent <= indent || nextIndent == len(next) || isYAMLKeyLine(next[nextIndent:]) {
+				break
+			}
+			value += " " + next[nextIndent:]
+			eol = nextEOL
+			i++
+		}
+
+		out, err := r.redactScalar(value)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(prefix + out + eol)
+	}
+
+	return b.String(), nil
+}
+
+// String returns a text representation of the redactor.
+func (r YAMLRedactor) String() string {
+	return fmt.Sprintf("{format=\"yaml\"; %s}", r.settings)
+}
+
+// blockScalar writes the content of the block scalar starting at lines[i]
+// whose key line has the specified indentation, redacted if matched, and
+// returns the index of the first line following it.
+func (r YAMLRedactor) blockScalar(b *strings.Builder, lines []string, i, parentIndent int, matched bool) (int, error) {
+	blockIndent := -1
+	end := i      // index of the first line following the block
+	lastLine := i // index following the last non-blank line of the block
+	for ; end < len(lines); end++ {
+		content, _ := splitLine(lines[end])
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		indent := countIndent(content)
+		if indent <= parentIndent || (blockIndent >= 0 && indent < blockIndent) {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = indent
+		}
+		lastLine = end + 1
+	}
+	end = lastLine
+
+	if !matched || blockIndent < 0 {
+		for _, line := range lines[i:end] {
+			b.WriteString(line)
+		}
+		return end, nil
+	}
+
+	var text []string
+	for _, line := range lines[i:end] {
+		content, _ := splitLine(line)
+		if len(content) >= blockIndent {
+			content = content[blockIndent:]
+		} else {
+			content = ""
+		}
+		text = append(text, content)
+	}
+
+	out, err := r.redact(strings.Join(text, "\n"))
+	if err != nil {
+		return 0, err
+	}
+
+	_, eol := splitLine(lines[i])
+	if eol == "" {
+		eol = "\n"
+	}
+	for _, line := range strings.Split(out, "\n") {
+		b.WriteString(strings.Repeat(" ", blockIndent) + line + eol)
+	}
+
+	return end, nil
+}
+
+// redactScalar redacts the scalar value, which follows a key or sequence
+// indicator and may start with whitespace, tags and anchors and end with a
+// comment, keeping its quoting.
+func (r YAMLRedactor) redactScalar(value string) (string, error) {
+	// leading whitespace, tags and anchors
+	start := 0
+	for {
+		for start < len(value) && value[start] == ' ' {
+			start++
+		}
+		if start < len(value) && (value[start] == '!' || value[start] == '&') {
+			next := strings.IndexByte(value[start:], ' ')
+			if next < 0 {
+				return value, nil // property without value
+			}
+			start += next
+			continue
+		}
+		break
+	}
+
+	if start == len(value) || value[start] == '*' {
+		return value, nil // no value, or an alias
+	}
+
+	text, end, quote := scanYAMLScalar(value, start)
+	if text == "" {
+		return value, nil
+	}
+
+	out, err := r.redact(text)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case quote == '\'':
+		out = "'" + strings.ReplaceAll(out, "'", "''") + "'"
+	case quote == '"' || needsYAMLQuotes(out):
+		out = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(out) + `"`
+	}
+
+	return value[:start] + out + value[end:], nil
+}
+
+// scanYAMLScalar scans the scalar starting at value[start] and returns its
+// text, the offset following it and its quote character, if any. Plain
+// scalars end before a comment or trailing whitespace, and quoted scalars
+// missing their closing quote at the end of the line.
+func scanYAMLScalar(value string, start int) (string, int, byte) {
+	switch q := value[start]; q {
+	case '\'':
+		var b strings.Builder
+		for i := start + 1; i < len(value); i++ {
+			if value[i] == '\'' {
+				if i+1 < len(value) && value[i+1] == '\'' {
+					b.WriteByte('\'')
+					i++
+					continue
+				}
+				return b.String(), i + 1, q
+			}
+			b.WriteByte(value[i])
+		}
+		return b.String(), len(value), q
+
+	case '"':
+		var b strings.Builder
+		for i := start + 1; i < len(value); i++ {
+			switch value[i] {
+			case '\\':
+				if i+1 < len(value) {
+					i++
+					b.WriteByte(value[i])
+					continue
+				}
+			case '"':
+				return b.String(), i + 1, q
+			}
+			b.WriteByte(value[i])
+		}
+		return b.String(), len(value), q
+	}
+
+	end := len(value)
+	if idx := strings.Index(value[start:], " #"); idx >= 0 {
+		end = start + idx
+	}
+	end = start + len(strings.TrimRight(value[start:end], " \t"))
+
+	return value[start:end], end, 0
+}
+
+// needsYAMLQuotes reports whether s must be quoted to be read back as the
+// same plain scalar.
+func needsYAMLQuotes(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+
+	if strings.ContainsAny(s[:1], "!&*[]{}|>'\"%@`#,?:-") {
+		return true
+	}
+
+	return strings.Contains(s, ": ") || strings.Contains(s, " #") ||
+		strings.HasSuffix(s, ":") || strings.ContainsAny(s, "\n\r\t")
+}
+
+// parseYAMLKey parses the mapping key at the start of s and returns it
+// along with the offset following its colon.
+func parseYAMLKey(s string) (string, int, bool) {
+	if s == "" || strings.ContainsRune("[{#&*!|>%@`?", rune(s[0])) {
+		return "", 0, false
+	}
+
+	var key string
+	i := 0
+	if s[0] == '"' || s[0] == '\'' {
+		text, end, _ := scanYAMLScalar(s, 0)
+		if end == len(s) || s[end-1] != s[0] {
+			return "", 0, false
+		}
+		key, i = text, end
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i == len(s) || s[i] != ':' {
+			return "", 0, false
+		}
+	} else {
+		for ; i < len(s); i++ {
+			if s[i] == ':' && (i+1 == len(s) || s[i+1] == ' ' || s[i+1] == '\t') {
+				break
+			}
+			if s[i] == '#' && i > 0 && s[i-1] == ' ' {
+				return "", 0, false
+			}
+		}
+		if i == len(s) {
+			return "", 0, false
+		}
+		key = strings.TrimRight(s[:i], " ")
+	}
+
+	return key, i + 1, true
+}
+
+// isYAMLKeyLine reports whether the line content s, without indentation,
+// holds a mapping key or a sequence entry.
+func isYAMLKeyLine(s string) bool {
+	if s == "-" || strings.HasPrefix(s, "- ") {
+		return true
+	}
+	_, _, ok := parseYAMLKey(s)
+	return ok
+}
+
+// yamlPath returns the key path of the innermost key on the stack.
+func yamlPath(stack []yamlKey) []string {
+	var keyPath []string
+	for _, k := range stack {
+		keyPath = append(keyPath, k.segments...)
+	}
+	return keyPath
+}
@@ -0,0 +1,117 @@
+package confredact
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kristinjeanna/redact/simple"
+)
+
+const replacement = "[redacted]"
+
+func TestYAMLRedactor(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"name: app\npassword: s3cret\n", "name: app\npassword: \"[redacted]\"\n"},
+		{"password: s3cret # rotate monthly\r\nname: app", "password: \"[redacted]\" # rotate monthly\r\nname: app"},
+		{"password: 's3''cret'\ntoken: \"a\\\"b\"\n", "password: '[redacted]'\ntoken: \"[redacted]\"\n"},
+		{"password:\nname: x\n", "password:\nname: x\n"},
+		{"password: \"\"\n", "password: \"\"\n"},
+		{"password: &pw !!str s3cret\nother: *pw\n", "password: &pw !!str \"[redacted]\"\nother: *pw\n"},
+		{"'password' : s3cret\n", "'password' : \"[redacted]\"\n"},
+		{
+			"db:\n  host: localhost\n  password: s3cret\n  pool:\n    size: 5\nlog:\n  password_policy: strict\n",
+			"db:\n  host: localhost\n  password: \"[redacted]\"\n  pool:\n    size: 5\nlog:\n  password_policy: \"[redacted]\"\n",
+		},
+		{
+			"db:\n  replicas:\n    - host: a\n      password: s1\n    - host: b\n      password: s2\nhost: c\n",
+			"db:\n  replicas:\n    - host: a\n      password: \"[redacted]\"\n    - host: b\n      password: \"[redacted]\"\nhost: c\n",
+		},
+		{
+			"credentials:\n  user: bob\n  keys:\n  - k1\n  - k2\nuser: alice\n",
+			"credentials:\n  user: \"[redacted]\"\n  keys:\n  - \"[redacted]\"\n  - \"[redacted]\"\nuser: alice\n",
+		},
+		{
+			"private_key: |\n  -----BEGIN KEY-----\n  abc\n\n  -----END KEY-----\n\nname: app\n",
+			"private_key: |\n  [redacted]\n\nname: app\n",
+		},
+		{
+			"script: |\n  password: not-a-key\n  echo hi\nname: app\n",
+			"script: |\n  password: not-a-key\n  echo hi\nname: app\n",
+		},
+		{"password: two\n  lines\nname: x\n", "password: \"[redacted]\"\nname: x\n"},
+		{"password: \"two\n  lines\" # c\nname: x\n", "password: \"[redacted]\" # c\nname: x\n"},
+		{"---\n# comment\npassword: a\n...\n", "---\n# comment\npassword: \"[redacted]\"\n...\n"},
+		{"spring.datasource.password: s3cret\n", "spring.datasource.password: \"[redacted]\"\n"},
+		{"password: {a: 1, b: 2}\n", "password: \"[redacted]\"\n"},
+		{"url: http://x:8080/path\n", "url: http://x:8080/path\n"},
+	}
+
+	redactor, err := NewYAML(simple.New(replacement))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestYAMLRedactor_quoting(t *testing.T) {
+	var tests = []struct {
+		replacement string
+		input       string
+		expected    string
+	}{
+		{"a: b", "password: x\n", "password: \"a: b\"\n"},
+		{"*", "password: x\n", "password: \"*\"\n"},
+		{"it's", "password: 'x'\n", "password: 'it''s'\n"},
+		{`a"b`, "password: \"x\"\n", "password: \"a\\\"b\"\n"},
+		{"line1\nline2", "password: |\n  x\n", "password: |\n  line1\n  line2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("replacement=%q", tt.replacement), func(t *testing.T) {
+			redactor, err := NewYAML(simple.New(tt.replacement))
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestYAMLRedactor_keys(t *testing.T) {
+	redactor, err := NewYAML(simple.New(replacement), WithKeys("db.user", "*.api.*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "user: a\ndb:\n  user: b\n  password: c\nsvc:\n  api:\n    id: d\n"
+	expected := "user: a\ndb:\n  user: \"[redacted]\"\n  password: c\nsvc:\n  api:\n    id: \"[redacted]\"\n"
+
+	actual, err := redactor.Redact(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
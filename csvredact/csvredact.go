@@ -0,0 +1,279 @@
+package csvredact
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kristinjeanna/redact"
+)
+
+var (
+	errNoColumns   = errors.New("csvredact.NewFromOptions: at least one column or a default redactor is required")
+	errNamesNoHead = errors.New("csvredact.NewFromOptions: columns can only be selected by name when the data has a header")
+
+	errMsgFmtInvalidDelimiter = "csvredact.NewFromOptions: invalid delimiter %q"
+	errMsgFmtInvalidIndex     = "csvredact.NewFromOptions: invalid column index %d"
+	errMsgFmtUnknownColumn    = "csvredact.CSVRedactor.RedactStream: no column named %q"
+	errMsgFmtRedactFailure    = "csvredact.CSVRedactor.RedactStream: error while redacting line %d, column %d, %w"
+)
+
+// CSVRedactor is a redactor for CSV data that applies a redactor to each
+// field of the selected columns. Columns are selected by header name or by
+// 0-based index. A default redactor, e.g. a regex redactor, may be applied
+// to the fields of the columns not selected. Empty fields are left empty.
+type CSVRedactor struct {
+	byName          map[string]redact.Redactor
+	byIndex         map[int]redact.Redactor
+	defaultRedactor redact.Redactor
+	comma           rune
+	header          bool
+	lazyQuotes      bool
+}
+
+// New returns a new CSVRedactor for comma-separated data with a header,
+// that redacts the columns with the names in the map using the
+// corresponding redactors. To stream data via RedactStream, assert the
+// returned redactor to a CSVRedactor.
+func New(columns map[string]redact.Redactor) redact.Redactor {
+	return CSVRedactor{
+		byName:  columns,
+		byIndex: map[int]redact.Redactor{},
+		comma:   ',',
+		header:  true,
+	}
+}
+
+// NewFromOptions returns a new CSVRedactor with the provided options.
+func NewFromOptions(opts ...Option) (redact.Redactor, error) {
+	r := New(map[string]redact.Redactor{}).(CSVRedactor)
+	for _, o := range opts {
+		o(&r)
+	}
+
+	if r.comma == 0 || r.comma == '"' || r.comma == '\r' || r.comma == '\n' ||
+		r.comma == utf8.RuneError || !utf8.ValidRune(r.comma) {
+		return nil, fmt.Errorf(errMsgFmtInvalidDelimiter, r.comma)
+	}
+
+	for idx := range r.byIndex {
+		if idx < 0 {
+			return nil, fmt.Errorf(errMsgFmtInvalidIndex, idx)
+		}
+	}
+
+	if len(r.byName) > 0 && !r.header {
+		return nil, errNamesNoHead
+	}
+
+	if len(r.byName) == 0 && len(r.byIndex) == 0 && r.defaultRedactor == nil {
+		return nil, errNoColumns
+	}
+
+	return r, nil
+}
+
+// Redact redacts the CSV data in s.
+func (r CSVRedactor) Redact(s string) (string, error) {
+	var b strings.Builder
+	if err := r.RedactStream(strings.NewReader(s), &b); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// RedactStream reads CSV data from src and writes it to dst with the
+// selected columns redacted. The header, if any, is written unchanged. The
+// output is written by a csv.Writer, so fields are quoted only where
+// needed, whatever their quoting in the input.
+func (r CSVRedactor) RedactStream(src io.Reader, dst io.Writer) error {
+	cr := csv.NewReader(src)
+	cr.Comma = r.comma
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = r.lazyQuotes
+	cr.ReuseRecord = true
+
+	cw := csv.NewWriter(dst)
+	cw.Comma = r.comma
+
+	var columns []redact.Redactor // redactor of each column by index
+	if !r.header {
+		columns = r.columnsByIndex(0)
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if columns == nil {
+			if columns, err = r.columnsByHeader(record); err != nil {
+				return err
+			}
+		} else if err := r.redactRecord(cr, record, columns); err != nil {
+			return err
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// String returns a text representation of the redactor.
+func (r CSVRedactor) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "{comma=%q; header=%t; ", r.comma, r.header)
+	if len(r.byName) > 0 {
+		fmt.Fprintf(&b, "columns=%v; ", r.byName)
+	}
+	if len(r.byIndex) > 0 {
+		fmt.Fprintf(&b, "indexes=%v; ", r.byIndex)
+	}
+	fmt.Fprintf(&b, "default=%v}", r.defaultRedactor)
+
+	return b.String()
+}
+
+// redactRecord redacts the fields of the record in place.
+func (r CSVRedactor) redactRecord(cr *csv.Reader, record []string, columns []redact.Redactor) error {
+	for i, field := range record {
+		redactor := r.defaultRedactor
+		if i < len(columns) {
+			redactor = columns[i]
+		}
+		if redactor == nil || field == "" {
+			continue
+		}
+
+		out, err := redactor.Redact(field)
+		if err != nil {
+			line, _ := cr.FieldPos(i)
+			return fmt.Errorf(errMsgFmtRedactFailure, line, i, err)
+		}
+		record[i] = out
+	}
+
+	return nil
+}
+
+// columnsByHeader returns the redactor of each column, resolving the
+// column names against the header. A column selected by name takes the
+// redactor set for its name over the one set for its index.
+func (r CSVRedactor) columnsByHeader(header []string) ([]redact.Redactor, error) {
+	columns := r.columnsByIndex(len(header))
+
+	found := make(map[string]bool, len(r.byName))
+	for i, name := range header {
+		if redactor, ok := r.byName[name]; ok {
+			columns[i] = redactor
+			found[name] = true
+		}
+	}
+
+	for name := range r.byName {
+		if !found[name] {
+			return nil, fmt.Errorf(errMsgFmtUnknownColumn, name)
+		}
+	}
+
+	return columns, nil
+}
+
+// columnsByIndex returns the redactor of each column selected by index, or
+// the default redactor. The slice covers at least n columns.
+func (r CSVRedactor) columnsByIndex(n int) []redact.Redactor {
+	for idx := range r.byIndex {
+		if idx >= n {
+			n = idx + 1
+		}
+	}
+
+	columns := make([]redact.Redactor, n)
+	for i := range columns {
+		if redactor, ok := r.byIndex[i]; ok {
+			columns[i] = redactor
+		} else {
+			columns[i] = r.defaultRedactor
+		}
+	}
+
+	return columns
+}
+
+// Option defines options for creating new CSV redactors.
+type Option func(*CSVRedactor)
+
+/*
+WithColumn sets the redactor for the column with the specified header name.
+Default is none.
+*/
+func WithColumn(name string, redactor redact.Redactor) Option {
+	return func(r *CSVRedactor) {
+		r.byName[name] = redactor
+	}
+}
+
+/*
+WithColumnIndex sets the redactor for the column with the specified 0-based
+index. Default is none.
+*/
+func WithColumnIndex(index int, redactor redact.Redactor) Option {
+	return func(r *CSVRedactor) {
+		r.byIndex[index] = redactor
+	}
+}
+
+/*
+WithDefault sets the redactor for the columns not selected by name or index,
+such as a regex redactor catching values that should not be there. Default
+is nil, which leaves such columns intact.
+*/
+func WithDefault(redactor redact.Redactor) Option {
+	return func(r *CSVRedactor) {
+		r.defaultRedactor = redactor
+	}
+}
+
+/*
+WithDelimiter sets the field delimiter, e.g. '\t' for TSV data. Default is
+','.
+*/
+func WithDelimiter(delimiter rune) Option {
+	return func(r *CSVRedactor) {
+		r.comma = delimiter
+	}
+}
+
+/*
+WithHeader sets whether the first row of the data is a header naming the
+columns. Without a header, columns can only be selected by index. Default is
+true.
+*/
+func WithHeader(header bool) Option {
+	return func(r *CSVRedactor) {
+		r.header = header
+	}
+}
+
+/*
+WithLazyQuotes sets whether quotes may appear in unquoted fields and
+non-doubled quotes in quoted fields, as with (*csv.Reader).LazyQuotes.
+Default is false.
+*/
+func WithLazyQuotes(lazyQuotes bool) Option {
+	return func(r *CSVRedactor) {
+		r.lazyQuotes = lazyQuotes
+	}
+}
@@ -0,0 +1,206 @@
+package csvredact
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/internal/redacttest"
+	"github.com/kristinjeanna/redact/middle"
+	"github.com/kristinjeanna/redact/regex"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func newMiddle(t *testing.T) redact.Redactor {
+	t.Helper()
+	r, err := middle.NewFromOptions(middle.WithReplacementText("***"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func newSSNRegex(t *testing.T) redact.Redactor {
+	t.Helper()
+	pair, err := regex.NewPairUsingSimple("[ssn]", regex.SSNRegex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := regex.New([]regex.Pair{*pair})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRedact(t *testing.T) {
+	input := "name,email,ssn,notes\n" +
+		"bob,bob.smith@example.com,123-45-6789,\"likes \"\"quotes\"\", commas\"\n" +
+		"alice,alice.jones@example.com,987-65-4321,\"multi\nline 111-22-3333\"\n" +
+		"eve,,,\n"
+
+	var tests = []struct {
+		opts     []Option
+		expected string
+	}{
+		{
+			[]Option{WithColumn("email", newMiddle(t)), WithColumn("ssn", simple.New("[redacted]"))},
+			"name,email,ssn,notes\n" +
+				"bob,bob***com,[redacted],\"likes \"\"quotes\"\", commas\"\n" +
+				"alice,ali***com,[redacted],\"multi\nline 111-22-3333\"\n" +
+				"eve,,,\n",
+		},
+		{
+			[]Option{WithColumnIndex(0, simple.New("[name]")), WithDefault(newSSNRegex(t))},
+			"name,email,ssn,notes\n" +
+				"[name],bob.smith@example.com,[ssn],\"likes \"\"quotes\"\", commas\"\n" +
+				"[name],alice.jones@example.com,[ssn],\"multi\nline [ssn]\"\n" +
+				"[name],,,\n",
+		},
+		{
+			[]Option{WithColumn("ssn", simple.New("[by name]")), WithColumnIndex(2, simple.New("[by index]"))},
+			"name,email,ssn,notes\n" +
+				"bob,bob.smith@example.com,[by name],\"likes \"\"quotes\"\", commas\"\n" +
+				"alice,alice.jones@example.com,[by name],\"multi\nline 111-22-3333\"\n" +
+				"eve,,,\n",
+		},
+		{
+			[]Option{WithHeader(false), WithColumnIndex(0, simple.New("[redacted]"))},
+			"[redacted],email,ssn,notes\n" +
+				"[redacted],bob.smith@example.com,123-45-6789,\"likes \"\"quotes\"\", commas\"\n" +
+				"[redacted],alice.jones@example.com,987-65-4321,\"multi\nline 111-22-3333\"\n" +
+				"[redacted],,,\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("opts=%d", len(tt.opts)), func(t *testing.T) {
+			redactor, err := NewFromOptions(tt.opts...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			actual, err := redactor.Redact(input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_tsv(t *testing.T) {
+	redactor, err := NewFromOptions(WithDelimiter('\t'), WithColumn("token", simple.New("[redacted]")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "user\ttoken\nbob\tabc,def\nalice\t\"x\ty\"\n"
+	expected := "user\ttoken\nbob\t[redacted]\nalice\t[redacted]\n"
+
+	actual, err := redactor.Redact(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
+
+func TestRedact_raggedRows(t *testing.T) {
+	redactor, err := NewFromOptions(WithColumnIndex(3, simple.New("X")), WithDefault(simple.New("-")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "a,b\n1\n1,2,3,4,5\n"
+	expected := "a,b\n-\n-,-,-,X,-\n"
+
+	actual, err := redactor.Redact(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
+
+func TestNew(t *testing.T) {
+	redactor := New(map[string]redact.Redactor{"pin": simple.New("****")}).(CSVRedactor)
+
+	var b strings.Builder
+	if err := redactor.RedactStream(strings.NewReader("card,pin\n4111,1234\n"), &b); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "card,pin\n4111,****\n"
+	if b.String() != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, b.String())
+	}
+}
+
+func TestNewFromOptions_errors(t *testing.T) {
+	var tests = []struct {
+		opts     []Option
+		expected string
+	}{
+		{nil, errNoColumns.Error()},
+		{[]Option{WithColumn("a", simple.New("")), WithHeader(false)}, errNamesNoHead.Error()},
+		{[]Option{WithColumnIndex(-1, simple.New(""))}, fmt.Sprintf(errMsgFmtInvalidIndex, -1)},
+		{[]Option{WithDefault(simple.New("")), WithDelimiter('"')}, fmt.Sprintf(errMsgFmtInvalidDelimiter, '"')},
+		{[]Option{WithDefault(simple.New("")), WithDelimiter('\n')}, fmt.Sprintf(errMsgFmtInvalidDelimiter, '\n')},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("expected=%q", tt.expected), func(t *testing.T) {
+			_, err := NewFromOptions(tt.opts...)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Expected error '%s', but got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestRedact_errors(t *testing.T) {
+	var tests = []struct {
+		opts     []Option
+		input    string
+		expected string
+	}{
+		{[]Option{WithColumn("missing", simple.New(""))}, "a,b\n1,2\n", fmt.Sprintf(errMsgFmtUnknownColumn, "missing")},
+		{[]Option{WithColumn("b", redacttest.Failing{})}, "a,b\n1,2\n", "csvredact.CSVRedactor.RedactStream: error while redacting line 2, column 1, failed"},
+		{[]Option{WithColumn("b", simple.New(""))}, "a,b\n1,\"2\n", "parse error on line 2, column 6: extraneous or missing \" in quoted-field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			redactor, err := NewFromOptions(tt.opts...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			_, err = redactor.Redact(tt.input)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Expected error '%s', but got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestRedact_lazyQuotes(t *testing.T) {
+	redactor, err := NewFromOptions(WithLazyQuotes(true), WithColumnIndex(1, simple.New("X")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := redactor.Redact("a,b\nsay \"hi\",1\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "a,b\n\"say \"\"hi\"\"\",X\n"
+	if actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
@@ -0,0 +1,4 @@
+// Package csvredact provides a redactor for CSV and TSV data that redacts
+// selected columns, each with its own redactor. Rows are streamed, so data
+// of any size can be redacted.
+package csvredact
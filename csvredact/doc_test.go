@@ -0,0 +1,50 @@
+package csvredact
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+
+	"github.com/kristinjeanna/redact/middle"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func ExampleNewFromOptions() {
+	emailRedactor, err := middle.NewFromOptions(middle.WithReplacementText("***"))
+	if err != nil {
+		log.Fatalf("an error occurred while creating redactor: %s", err)
+	}
+
+	redactor, err := NewFromOptions(
+		WithColumn("email", emailRedactor),
+		WithColumn("ssn", simple.New("XXX-XX-XXXX")),
+	)
+	if err != nil {
+		log.Fatalf("an error occurred while creating redactor: %s", err)
+	}
+
+	result, err := redactor.Redact("name,email,ssn\nbob,bob.smith@example.com,123-45-6789\n")
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+
+	fmt.Print(result)
+	// Output:
+	// name,email,ssn
+	// bob,bob***com,XXX-XX-XXXX
+}
+
+func ExampleCSVRedactor_RedactStream() {
+	redactor := New(map[string]redact.Redactor{"pin": simple.New("****")})
+
+	src := strings.NewReader("card,pin\n4111,1234\n")
+	if err := redactor.(CSVRedactor).RedactStream(src, os.Stdout); err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+	// Output:
+	// card,pin
+	// 4111,****
+}
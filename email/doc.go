@@ -0,0 +1,5 @@
+// Package email provides a redactor for email addresses found in text. The
+// local part and the domain of each address are redacted separately, so
+// that, for example, only the mailbox is hidden while the domain remains
+// visible.
+package email
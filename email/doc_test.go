@@ -0,0 +1,27 @@
+package email
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func ExampleNewFromOptions() {
+	redactor, err := NewFromOptions(
+		WithLocalPart(simple.New("***")),
+		WithDomain(simple.New("[domain]")),
+		WithAllowedDomains("ourcompany.com"),
+	)
+	if err != nil {
+		log.Fatalf("an error occurred while creating redactor: %s", err)
+	}
+
+	result, err := redactor.Redact("From: jane.doe@ourcompany.com, To: bob@example.org")
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+
+	fmt.Println(result)
+	// Output: From: ***@ourcompany.com, To: ***@[domain]
+}
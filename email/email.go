@@ -0,0 +1,158 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+// addressRegex matches RFC 5322 style addresses: a dot-atom or quoted local
+// part, and a domain name or address literal. Atoms are limited to the
+// characters commonly used in addresses, with apostrophes only inside an
+// atom, so that the syntax around addresses in logs, such as "key=" or
+// quotes, is not taken for part of the local part.
+var addressRegex = regexp.MustCompile(
+	`(?P<local>` + localAtom + `(?:\.` + localAtom + `)*|"(?:[^"\\\r\n]|\\.)*")` +
+		`@` +
+		`(?P<domain>(?:[\p{L}\p{N}](?:[\p{L}\p{N}-]*[\p{L}\p{N}])?\.)+(?:\p{L}{2,}|xn--[A-Za-z0-9-]+)|\[(?:IPv6:)?[0-9A-Fa-f.:]+\])`)
+
+// localAtom matches an atom of a dot-atom local part.
+const localAtom = `[\p{L}\p{N}_%+-](?:[\p{L}\p{N}_%+'-]*[\p{L}\p{N}_%+-])?`
+
+var (
+	localIndex  = addressRegex.SubexpIndex("local")
+	domainIndex = addressRegex.SubexpIndex("domain")
+)
+
+var (
+	errRedactorsNil = errors.New("email.NewFromOptions: a local part or domain redactor is required")
+
+	errMsgFmtRedactFailure = "email.EmailRedactor.Redact: error while redacting, %w"
+)
+
+// EmailRedactor is a redactor that replaces the local part, the domain, or
+// both parts of the email addresses in text with the output of a separate
+// redactor for each part. The domains in an allow-list are never redacted.
+type EmailRedactor struct {
+	localRedactor  redact.Redactor
+	domainRedactor redact.Redactor
+	allowedDomains []string
+}
+
+// New returns a new EmailRedactor that redacts the local part of addresses
+// with the specified redactor, keeping their domains.
+func New(redactor redact.Redactor) redact.Redactor {
+	return EmailRedactor{localRedactor: redactor}
+}
+
+// NewFromOptions returns a new EmailRedactor with the provided options. At
+// least one of the local part and domain redactors must be set.
+func NewFromOptions(opts ...Option) (redact.Redactor, error) {
+	var r EmailRedactor
+	for _, o := range opts {
+		o(&r)
+	}
+
+	if r.localRedactor == nil && r.domainRedactor == nil {
+		return nil, errRedactorsNil
+	}
+
+	return r, nil
+}
+
+// Redact replaces the parts of the email addresses in s.
+func (r EmailRedactor) Redact(s string) (string, error) {
+	spans, err := r.Detect(s)
+	if err != nil {
+		return "", err
+	}
+
+	return redact.ApplySpans(s, spans), nil
+}
+
+// Detect returns the spans of the parts of the email addresses in s that are
+// redacted.
+func (r EmailRedactor) Detect(s string) ([]redact.Span, error) {
+	var spans []redact.Span
+
+	for _, m := range addressRegex.FindAllStringSubmatchIndex(s, -1) {
+		local := [2]int{m[2*localIndex], m[2*localIndex+1]}
+		domain := [2]int{m[2*domainIndex], m[2*domainIndex+1]}
+
+		if r.localRedactor != nil {
+			repl, err := r.localRedactor.Redact(s[local[0]:local[1]])
+			if err != nil {
+				return nil, fmt.Errorf(errMsgFmtRedactFailure, err)
+			}
+			spans = append(spans, redact.Span{Start: local[0], End: local[1], Replacement: repl})
+		}
+
+		if r.domainRedactor != nil && !r.isAllowed(s[domain[0]:domain[1]]) {
+			repl, err := r.domainRedactor.Redact(s[domain[0]:domain[1]])
+			if err != nil {
+				return nil, fmt.Errorf(errMsgFmtRedactFailure, err)
+			}
+			spans = append(spans, redact.Span{Start: domain[0], End: domain[1], Replacement: repl})
+		}
+	}
+
+	return spans, nil
+}
+
+// isAllowed reports whether the domain is, or is a subdomain of, an allowed
+// domain.
+func (r EmailRedactor) isAllowed(domain string) bool {
+	domain = strings.ToLower(domain)
+	for _, allowed := range r.allowedDomains {
+		if domain == allowed || strings.HasSuffix(domain, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a text representation of the redactor.
+func (r EmailRedactor) String() string {
+	return fmt.Sprintf("{localRedactor=%v; domainRedactor=%v; allowedDomains=%q}",
+		r.localRedactor, r.domainRedactor, r.allowedDomains)
+}
+
+// Option defines options for creating new email redactors.
+type Option func(*EmailRedactor)
+
+/*
+WithLocalPart sets the redactor for the local part of addresses, the part
+before the "@". Default is nil, which keeps local parts.
+*/
+func WithLocalPart(redactor redact.Redactor) Option {
+	return func(r *EmailRedactor) {
+		r.localRedactor = redactor
+	}
+}
+
+/*
+WithDomain sets the redactor for the domain of addresses, the part after the
+"@". Default is nil, which keeps domains.
+*/
+func WithDomain(redactor redact.Redactor) Option {
+	return func(r *EmailRedactor) {
+		r.domainRedactor = redactor
+	}
+}
+
+/*
+WithAllowedDomains sets the domains, ignoring case, that are never redacted,
+along with their subdomains. The local parts of addresses in these domains
+are still redacted. Default is no allowed domains.
+*/
+func WithAllowedDomains(domains ...string) Option {
+	return func(r *EmailRedactor) {
+		r.allowedDomains = make([]string, 0, len(domains))
+		for _, d := range domains {
+			r.allowedDomains = append(r.allowedDomains, strings.ToLower(strings.TrimPrefix(d, "@")))
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/internal/redacttest"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+// firstLetter keeps the first letter of its input.
+var firstLetter = redacttest.Func(func(s string) (string, error) {
+	return s[:1] + "***", nil
+})
+
+func TestRedact_localParts(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"no addresses here", "no addresses here"},
+		{"jane@example.com", "j***@example.com"},
+		{"Contact jane.doe+tag@mail.example.co.uk.", "Contact j***@mail.example.co.uk."},
+		{`"jane doe"@example.com`, `"***@example.com`},
+		{"jösé@exämple.de", "j***@exämple.de"},
+	}
+
+	redactor := New(firstLetter)
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_domains(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"jane@[192.0.2.1]", "j***@[192.0.2.1]"},
+		{"jane@[IPv6:2001:db8::1]", "j***@[IPv6:2001:db8::1]"},
+		{"jane@xn--bcher-kva.xn--tckwe", "j***@xn--bcher-kva.xn--tckwe"},
+		{"jane@localhost", "jane@localhost"},
+		{"jane@example.c", "jane@example.c"},
+		{"jane@-example.com", "jane@-example.com"},
+	}
+
+	redactor := New(firstLetter)
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_surroundingText(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"<jane@example.com>, bob@example.org", "<j***@example.com>, b***@example.org"},
+		{"user=bob@example.com", "user=b***@example.com"},
+		{"'bob@example.com'", "'b***@example.com'"},
+		{"`bob@example.com`", "`b***@example.com`"},
+		{`msg="bob@example.com"`, `msg="b***@example.com"`},
+		{"{bob@example.com}|{eve@example.com}", "{b***@example.com}|{e***@example.com}"},
+		{"o'brien@example.com", "o***@example.com"},
+		{"mailto:jane@example.com?subject=hi", "mailto:j***@example.com?subject=hi"},
+		{"@example.com", "@example.com"},
+		{"v1.2@3", "v1.2@3"},
+	}
+
+	redactor := New(firstLetter)
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithAllowedDomains(t *testing.T) {
+	redactor, err := NewFromOptions(WithLocalPart(simple.New("[user]")), WithDomain(simple.New("[domain]")),
+		WithAllowedDomains("@OurCompany.com"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// the local part is redacted even for allowed domains
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"jane@ourcompany.com", "[user]@ourcompany.com"},
+		{"bob@dev.OURCOMPANY.com", "[user]@dev.OURCOMPANY.com"},
+		{"eve@notourcompany.com", "[user]@[domain]"},
+		{"eve@ourcompany.com.evil.org", "[user]@[domain]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_singlePart(t *testing.T) {
+	var tests = []struct {
+		opt      Option
+		expected string
+	}{
+		{WithLocalPart(simple.New("[user]")), "to: [user]@example.com"},
+		{WithDomain(simple.New("[domain]")), "to: jane@[domain]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			redactor, err := NewFromOptions(tt.opt)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			actual, err := redactor.Redact("to: jane@example.com")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_domainRedactorSkippedForAllowedDomains(t *testing.T) {
+	redactor, err := NewFromOptions(WithDomain(redacttest.Failing{}), WithAllowedDomains("example.com"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := redactor.Redact("jane@example.com"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := redactor.Redact("jane@example.org"); !errors.Is(err, redacttest.ErrFailed) {
+		t.Errorf("Expected '%v', but got '%v'", redacttest.ErrFailed, err)
+	}
+}
+
+func TestDetect_separateParts(t *testing.T) {
+	redactor, err := NewFromOptions(WithLocalPart(simple.New("x")), WithDomain(simple.New("y")))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	spans, err := redactor.(redact.Detector).Detect("to jane@example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// the "@" between the spans is kept
+	expected := []redact.Span{{Start: 3, End: 7, Replacement: "x"}, {Start: 8, End: 19, Replacement: "y"}}
+	if fmt.Sprint(spans) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%v', but got '%v'", expected, spans)
+	}
+}
+
+func TestNewFromOptions_noRedactors(t *testing.T) {
+	if _, err := NewFromOptions(WithAllowedDomains("example.com")); err != errRedactorsNil {
+		t.Errorf("Expected '%v', but got '%v'", errRedactorsNil, err)
+	}
+}
+
+func TestString(t *testing.T) {
+	redactor, err := NewFromOptions(WithDomain(simple.New("x")), WithAllowedDomains("Example.com"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{localRedactor=<nil>; domainRedactor={replacement="x"}; allowedDomains=["example.com"]}`
+	if actual := fmt.Sprint(redactor); actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
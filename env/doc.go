@@ -0,0 +1,12 @@
+// Package env provides a redactor for environment variables and command-line
+// arguments, such as those returned by os.Environ and held in os.Args. The
+// values of variables and flags whose names denote secrets are replaced by
+// the output of another redactor.
+//
+// New and NewFromOptions return a redact.Redactor, which redacts text holding
+// one KEY=VALUE pair per line. To redact argument and environment slices,
+// assert it to an EnvRedactor and call RedactArgs or RedactEnv:
+//
+//	redactor := env.New(nil).(env.EnvRedactor)
+//	args, err := redactor.RedactArgs(os.Args)
+package env
@@ -0,0 +1,27 @@
+package env
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func ExampleEnvRedactor_RedactArgs() {
+	redactor := New(simple.New("[redacted]")).(EnvRedactor)
+
+	args, err := redactor.RedactArgs([]string{"deploy", "--token", "abc123", "--password=hunter2", "--verbose"})
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+	fmt.Println(args)
+
+	environ, err := redactor.RedactEnv([]string{"HOME=/home/bob", "GITHUB_TOKEN=ghp_abc123"})
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+	fmt.Println(environ)
+	// Output:
+	// [deploy --token [redacted] --password=[redacted] --verbose]
+	// [HOME=/home/bob GITHUB_TOKEN=[redacted]]
+}
@@ -0,0 +1,373 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+const defaultReplacementText = "[redacted]"
+
+var (
+	errRedactorNil = errors.New("env.NewFromOptions: redactor must not be nil")
+
+	errMsgFmtBadPattern    = "env.NewFromOptions: invalid key pattern %q"
+	errMsgFmtRedactFailure = "env.EnvRedactor.Redact: error while redacting, %w"
+)
+
+// DefaultKeyPatterns returns the patterns matching the keys of environment
+// variables whose values are redacted by default.
+func DefaultKeyPatterns() []string {
+	return []string{
+		"*PASSWORD*",
+		"*PASSWD*",
+		"*SECRET*",
+		"*_TOKEN",
+		"*_API_KEY",
+		"*_PRIVATE_KEY",
+		"*CREDENTIALS*",
+		"AWS_SECRET_ACCESS_KEY",
+	}
+}
+
+// DefaultFlags returns the names of the command-line flags whose values are
+// redacted by default. Short flags such as "p" are left out, since they
+// commonly stand for a port, profile or parallelism as well; add them via
+// WithFlags for commands like "mysql -p secret".
+func DefaultFlags() []string {
+	return []string{
+		"password",
+		"passwd",
+		"pass",
+		"secret",
+		"token",
+		"api-key",
+		"access-token",
+		"client-secret",
+	}
+}
+
+// EnvRedactor is a redactor for environment variables in KEY=VALUE form
+// and for command-line arguments. The value of a variable is redacted when
+// its key matches one of the key patterns, or when one of the value
+// detectors finds something to redact in it.
+type EnvRedactor struct {
+	redactor    redact.Redactor
+	keyPatterns []string
+	flags       map[string]bool
+	detectors   []redact.Detector
+	attached    bool // single-letter flags may have attached values
+}
+
+// New returns a new EnvRedactor that replaces values with the output of
+// the redactor, using the default key patterns and flags. If the redactor
+// is nil, values are replaced with "[redacted]". To redact argument and
+// environment slices, assert the returned redactor to an EnvRedactor.
+func New(redactor redact.Redactor) redact.Redactor {
+	if redactor == nil {
+		redactor = simple.New(defaultReplacementText)
+	}
+
+	return EnvRedactor{
+		redactor:    redactor,
+		keyPatterns: upper(DefaultKeyPatterns()),
+		flags:       flagSet(DefaultFlags()),
+	}
+}
+
+// NewFromOptions returns a new EnvRedactor that replaces values with the
+// output of the redactor, with the provided options.
+func NewFromOptions(redactor redact.Redactor, opts ...Option) (redact.Redactor, error) {
+	if redactor == nil {
+		return nil, errRedactorNil
+	}
+
+	r := New(redactor).(EnvRedactor)
+	for _, o := range opts {
+		o(&r)
+	}
+
+	for _, p := range r.keyPatterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf(errMsgFmtBadPattern, p)
+		}
+	}
+
+	return r, nil
+}
+
+// Redact redacts the values of the environment variables in s, which holds
+// one KEY=VALUE pair per line, as printed by the env command. Lines of
+// other forms are left intact.
+func (r EnvRedactor) Redact(s string) (string, error) {
+	lines := strings.Split(s, "\n")
+
+	redacted, err := r.RedactEnv(lines)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(redacted, "\n"), nil
+}
+
+// RedactEnv returns a copy of environ, a slice of KEY=VALUE pairs as
+// returned by os.Environ, with the values of the matching variables
+// redacted.
+func (r EnvRedactor) RedactEnv(environ []string) ([]string, error) {
+	redacted := make([]string, len(environ))
+	for i, kv := range environ {
+		var err error
+		if redacted[i], err = r.redactPair(kv); err != nil {
+			return nil, err
+		}
+	}
+
+	return redacted, nil
+}
+
+/*
+RedactArgs returns a copy of args, a slice of command-line arguments as held
+in os.Args, with the values of the matching flags redacted. Flags start with
+one or two dashes and take their value either in the same argument, as in
+"--password=x", or in the next one, as in "--token x". A next argument that
+is itself a flag, as in "--token --verbose", is not taken for a value. With
+WithAttachedValues, the value of a single-letter flag may also directly
+follow its name, as in "-px". Arguments of the form KEY=VALUE are redacted
+like environment variables. Arguments after a "--" argument are left intact.
+*/
+func (r EnvRedactor) RedactArgs(args []string) ([]string, error) {
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+
+	for i := 0; i < len(redacted); i++ {
+		arg := redacted[i]
+		if arg == "--" {
+			break
+		}
+
+		if !isFlag(arg) {
+			var err error
+			if redacted[i], err = r.redactPair(arg); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		flag := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		name, value, hasValue := strings.Cut(flag, "=")
+
+		switch {
+		case hasValue && r.isSecretFlag(name):
+			out, err := r.redactValue(value)
+			if err != nil {
+				return nil, err
+			}
+			redacted[i] = arg[:len(arg)-len(value)] + out
+
+		case hasValue:
+			// value of a non-secret flag, redacted if detected as a secret
+			out, err := r.redactDetected(value)
+			if err != nil {
+				return nil, err
+			}
+			redacted[i] = arg[:len(arg)-len(value)] + out
+
+		case r.isSecretFlag(name):
+			if i+1 < len(redacted) && !isFlag(redacted[i+1]) {
+				out, err := r.redactValue(redacted[i+1])
+				if err != nil {
+					return nil, err
+				}
+				redacted[i+1] = out
+				i++
+			}
+
+		case r.attached && !strings.HasPrefix(arg, "--") && len(name) > 1 && r.isSecretFlag(name[:1]):
+			// single-letter flag with an attached value
+			out, err := r.redactValue(name[1:])
+			if err != nil {
+				return nil, err
+			}
+			redacted[i] = arg[:2] + out
+		}
+	}
+
+	return redacted, nil
+}
+
+// isFlag reports whether arg is a flag rather than the value of the flag
+// before it. A lone dash, commonly standing for standard input, is a value.
+func isFlag(arg string) bool {
+	return strings.HasPrefix(arg, "-") && arg != "-"
+}
+
+// String returns a text representation of the redactor.
+func (r EnvRedactor) String() string {
+	flags := make([]string, 0, len(r.flags))
+	for f := range r.flags {
+		flags = append(flags, f)
+	}
+	sort.Strings(flags)
+
+	return fmt.Sprintf("{keyPatterns=%q; flags=%q; detectors=%d; redactor=%v}",
+		r.keyPatterns, flags, len(r.detectors), r.redactor)
+}
+
+// redactPair redacts the value of kv if it is a KEY=VALUE pair whose key
+// matches a key pattern or whose value is detected as a secret.
+func (r EnvRedactor) redactPair(kv string) (string, error) {
+	key, value, ok := strings.Cut(kv, "=")
+	if !ok || !isKey(key) {
+		return kv, nil
+	}
+
+	var out string
+	var err error
+	if r.isSecretKey(key) {
+		out, err = r.redactValue(value)
+	} else {
+		out, err = r.redactDetected(value)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return key + "=" + out, nil
+}
+
+// redactDetected redacts value if a value detector finds a secret in it.
+func (r EnvRedactor) redactDetected(value string) (string, error) {
+	for _, d := range r.detectors {
+		spans, err := d.Detect(value)
+		if err != nil {
+			return "", fmt.Errorf(errMsgFmtRedactFailure, err)
+		}
+		if len(spans) > 0 {
+			return r.redactValue(value)
+		}
+	}
+
+	return value, nil
+}
+
+// redactValue returns the output of the redactor for value.
+func (r EnvRedactor) redactValue(value string) (string, error) {
+	out, err := r.redactor.Redact(value)
+	if err != nil {
+		return "", fmt.Errorf(errMsgFmtRedactFailure, err)
+	}
+	return out, nil
+}
+
+// isSecretKey reports whether the key matches a key pattern, ignoring
+// case.
+func (r EnvRedactor) isSecretKey(key string) bool {
+	key = strings.ToUpper(key)
+	for _, p := range r.keyPatterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isSecretFlag reports whether the flag name, without its leading dashes,
+// is one of the redacted flags. Long names are compared ignoring case and
+// treating "_" like "-".
+func (r EnvRedactor) isSecretFlag(name string) bool {
+	if len(name) > 1 {
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	}
+	return r.flags[name]
+}
+
+// isKey reports whether s is a valid environment variable name.
+func isKey(s string) bool {
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		return false
+	}
+
+	for _, c := range s {
+		if c != '_' && (c < '0' || c > '9') && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// upper returns the patterns in upper case.
+func upper(patterns []string) []string {
+	upper := make([]string, len(patterns))
+	for i, p := range patterns {
+		upper[i] = strings.ToUpper(p)
+	}
+	return upper
+}
+
+// flagSet returns the set of flag names, with long names in lower case.
+func flagSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		n = strings.TrimLeft(n, "-")
+		if len(n) > 1 {
+			n = strings.ReplaceAll(strings.ToLower(n), "_", "-")
+		}
+		set[n] = true
+	}
+	return set
+}
+
+// Option defines options for creating new env redactors.
+type Option func(*EnvRedactor)
+
+/*
+WithKeyPatterns sets the patterns matching the keys of the environment
+variables to redact, replacing the default ones. Patterns follow the syntax
+of path.Match and are matched ignoring case. Default is the patterns
+returned by DefaultKeyPatterns.
+*/
+func WithKeyPatterns(patterns ...string) Option {
+	return func(r *EnvRedactor) {
+		r.keyPatterns = upper(patterns)
+	}
+}
+
+/*
+WithFlags sets the names of the command-line flags to redact, replacing the
+default ones. Leading dashes are ignored. Default is the names returned by
+DefaultFlags.
+*/
+func WithFlags(names ...string) Option {
+	return func(r *EnvRedactor) {
+		r.flags = flagSet(names)
+	}
+}
+
+/*
+WithAttachedValues sets whether the value of a single-letter flag to redact
+may directly follow its name, as in "mysql -psecret". When enabled, arguments
+such as "-parallel" or "-port" are taken for a flag "p" with an attached
+value, so only enable it for commands without single-dash long flags starting
+with the letter of a flag to redact. Default is false.
+*/
+func WithAttachedValues(enabled bool) Option {
+	return func(r *EnvRedactor) {
+		r.attached = enabled
+	}
+}
+
+/*
+WithValueDetectors sets detectors that are run on the values of variables and
+flags not matched by name. When a detector finds anything to redact, the
+entire value is redacted. Default is none.
+*/
+func WithValueDetectors(detectors ...redact.Detector) Option {
+	return func(r *EnvRedactor) {
+		r.detectors = detectors
+	}
+}
@@ -0,0 +1,251 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/internal/redacttest"
+	"github.com/kristinjeanna/redact/regex"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+const replacement = "[redacted]"
+
+func newTokenDetector(t *testing.T) redact.Detector {
+	t.Helper()
+	pair, err := regex.NewPairUsingSimple(replacement, `^gh[pousr]_[A-Za-z0-9]{8,}$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := regex.New([]regex.Pair{*pair})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r.(redact.Detector)
+}
+
+func TestRedactEnv(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"HOME=/home/bob", "HOME=/home/bob"},
+		{"DB_PASSWORD=hunter2", "DB_PASSWORD=" + replacement},
+		{"db_password=hunter2", "db_password=" + replacement},
+		{"GITHUB_TOKEN=abc", "GITHUB_TOKEN=" + replacement},
+		{"TOKEN_FILE=/run/token", "TOKEN_FILE=/run/token"},
+		{"AWS_SECRET_ACCESS_KEY=wJalr", "AWS_SECRET_ACCESS_KEY=" + replacement},
+		{"STRIPE_API_KEY=sk_live", "STRIPE_API_KEY=" + replacement},
+		{"PASSWORD=", "PASSWORD=" + replacement},
+		{"SECRET=a=b", "SECRET=" + replacement},
+		{"EXTRA=ghp_abcdefgh1234", "EXTRA=" + replacement},
+		{"EXTRA=ghp_", "EXTRA=ghp_"},
+		{"not a pair", "not a pair"},
+		{"=C:=C:\\", "=C:=C:\\"},
+	}
+
+	redactor, err := NewFromOptions(simple.New(replacement), WithValueDetectors(newTokenDetector(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.(EnvRedactor).RedactEnv([]string{tt.input})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual[0] != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual[0])
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	input := "PATH=/usr/bin\nAPI_PASSWORD=s3cr3t\n\nUSER=bob\n"
+	expected := "PATH=/usr/bin\nAPI_PASSWORD=" + replacement + "\n\nUSER=bob\n"
+
+	actual, err := New(simple.New(replacement)).Redact(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
+
+func TestNew_nilRedactor(t *testing.T) {
+	actual, err := New(nil).Redact("API_PASSWORD=s3cr3t")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "API_PASSWORD=" + defaultReplacementText; actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	var tests = []struct {
+		input    []string
+		expected []string
+	}{
+		{[]string{"app", "--verbose", "file"}, []string{"app", "--verbose", "file"}},
+		{[]string{"app", "--password=x"}, []string{"app", "--password=" + replacement}},
+		{[]string{"app", "-password=x"}, []string{"app", "-password=" + replacement}},
+		{[]string{"app", "--token", "x", "file"}, []string{"app", "--token", replacement, "file"}},
+		{[]string{"app", "--Client_Secret", "x"}, []string{"app", "--Client_Secret", replacement}},
+		{[]string{"app", "--token"}, []string{"app", "--token"}},
+		{[]string{"app", "--token", "--verbose", "x"}, []string{"app", "--token", "--verbose", "x"}},
+		{[]string{"app", "--password", "-v"}, []string{"app", "--password", "-v"}},
+		{[]string{"mysql", "-p", "x", "-u", "bob"}, []string{"mysql", "-p", "x", "-u", "bob"}},
+		{[]string{"mysql", "-px"}, []string{"mysql", "-px"}},
+		{[]string{"go", "test", "-parallel", "4", "-pprof"}, []string{"go", "test", "-parallel", "4", "-pprof"}},
+		{[]string{"app", "-port", "8080"}, []string{"app", "-port", "8080"}},
+		{[]string{"app", "--px"}, []string{"app", "--px"}},
+		{[]string{"app", "--api-key=abc", "--port=80"}, []string{"app", "--api-key=" + replacement, "--port=80"}},
+		{[]string{"app", "--header=ghp_abcdefgh1234"}, []string{"app", "--header=" + replacement}},
+		{[]string{"env", "DB_PASSWORD=x", "app"}, []string{"env", "DB_PASSWORD=" + replacement, "app"}},
+		{[]string{"app", "--", "--password=x"}, []string{"app", "--", "--password=x"}},
+		{[]string{"app", "-", "--password", "-"}, []string{"app", "-", "--password", replacement}},
+	}
+
+	redactor, err := NewFromOptions(simple.New(replacement), WithValueDetectors(newTokenDetector(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.(EnvRedactor).RedactArgs(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Expected '%q', but got '%q'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedactArgs_attachedValues(t *testing.T) {
+	var tests = []struct {
+		input    []string
+		expected []string
+	}{
+		{[]string{"mysql", "-px", "-u", "bob"}, []string{"mysql", "-p" + replacement, "-u", "bob"}},
+		{[]string{"mysql", "-p", "x"}, []string{"mysql", "-p", replacement}},
+		{[]string{"mysql", "-p", "--verbose"}, []string{"mysql", "-p", "--verbose"}},
+		{[]string{"app", "--px"}, []string{"app", "--px"}},
+		{[]string{"app", "-ux"}, []string{"app", "-ux"}},
+		{[]string{"app", "-pprof"}, []string{"app", "-p" + replacement}},
+	}
+
+	redactor, err := NewFromOptions(simple.New(replacement), WithFlags("password", "p"), WithAttachedValues(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.(EnvRedactor).RedactArgs(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Expected '%q', but got '%q'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedactArgs_doesNotModifyInput(t *testing.T) {
+	args := []string{"app", "--password", "x"}
+
+	if _, err := New(simple.New(replacement)).(EnvRedactor).RedactArgs(args); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if args[2] != "x" {
+		t.Errorf("Expected 'x', but got '%s'", args[2])
+	}
+}
+
+func TestOptions(t *testing.T) {
+	redactor, err := NewFromOptions(simple.New(replacement),
+		WithKeyPatterns("*_pin", "session"),
+		WithFlags("--pin", "S"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		input    []string
+		expected []string
+	}{
+		{[]string{"CARD_PIN=1234", "SESSION=abc", "DB_PASSWORD=x"}, []string{"CARD_PIN=" + replacement, "SESSION=" + replacement, "DB_PASSWORD=x"}},
+		{[]string{"--pin", "1234", "-S", "abc", "-sabc", "--password=x"}, []string{"--pin", replacement, "-S", replacement, "-sabc", "--password=x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.(EnvRedactor).RedactArgs(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Expected '%q', but got '%q'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewFromOptions_errors(t *testing.T) {
+	var tests = []struct {
+		redactor redact.Redactor
+		opts     []Option
+		expected string
+	}{
+		{nil, nil, errRedactorNil.Error()},
+		{simple.New(replacement), []Option{WithKeyPatterns("[")}, fmt.Sprintf(errMsgFmtBadPattern, "[")},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("expected=%q", tt.expected), func(t *testing.T) {
+			_, err := NewFromOptions(tt.redactor, tt.opts...)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Expected error '%s', but got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestRedact_errors(t *testing.T) {
+	redactor := New(redacttest.Failing{})
+
+	if _, err := redactor.Redact("DB_PASSWORD=x"); !errors.Is(err, redacttest.ErrFailed) {
+		t.Errorf("Expected '%v', but got '%v'", redacttest.ErrFailed, err)
+	}
+	if _, err := redactor.(EnvRedactor).RedactArgs([]string{"--token", "x"}); !errors.Is(err, redacttest.ErrFailed) {
+		t.Errorf("Expected '%v', but got '%v'", redacttest.ErrFailed, err)
+	}
+	// arguments after "--" are never redacted
+	if _, err := redactor.(EnvRedactor).RedactArgs([]string{"--", "--token", "x"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestString(t *testing.T) {
+	redactor, err := NewFromOptions(simple.New(replacement), WithKeyPatterns("*_PIN"), WithFlags("pin", "p"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{keyPatterns=["*_PIN"]; flags=["p" "pin"]; detectors=0; redactor={replacement="[redacted]"}}`
+	if got := fmt.Sprint(redactor); got != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
@@ -0,0 +1,11 @@
+// Package ids provides validated detectors for national and financial
+// identifiers: US Social Security numbers, IBANs, UK National Insurance
+// numbers, Canadian Social Insurance numbers, and US Employer and Individual
+// Taxpayer Identification numbers. Unlike a plain regex, such as
+// regex.SSNRegex, each detector checks the structure and checksum of its
+// matches, so that only real-looking identifiers are redacted, and not, for
+// example, ZIP+4 codes or invoice numbers.
+//
+// The detectors are exposed as regex.Matcher implementations and regex
+// pairs, so that they can be combined with other rules in a regex redactor.
+package ids
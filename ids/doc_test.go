@@ -0,0 +1,23 @@
+package ids
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func ExampleNew() {
+	redactor, err := New(simple.New("[redacted]"))
+	if err != nil {
+		log.Fatalf("an error occurred while creating redactor: %s", err)
+	}
+
+	result, err := redactor.Redact("SSN 123-45-6789, ZIP 12345-6789, invoice 000-12-3456, IBAN GB82 WEST 1234 5698 7654 32")
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+
+	fmt.Println(result)
+	// Output: SSN [redacted], ZIP 12345-6789, invoice 000-12-3456, IBAN [redacted]
+}
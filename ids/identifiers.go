@@ -0,0 +1,122 @@
+package ids
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// SSN: area 001-899 except 666, group 01-99, serial 0001-9999
+	ssnMatcher = newMatcher(`\b\d{3}[- ]?\d{2}[- ]?\d{4}\b`, func(c string) bool {
+		d := digits(c)
+		area, group, serial := d[:3], d[3:5], d[5:]
+		return consistentSeparators(c, 3) &&
+			area != "000" && area != "666" && area[0] != '9' &&
+			group != "00" && serial != "0000"
+	})
+
+	// IBAN: country code, check digits and BBAN of the length defined for
+	// the country, optionally in groups of four, with a valid mod-97
+	// checksum. Candidates are cut to the length for the country, since the
+	// regex may take in a following group, as in "ES91... 2024-01-01".
+	ibanMatcher = newSizedMatcher(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`, ibanSize, func(c string) bool {
+		iban := strings.ReplaceAll(c, " ", "")
+		length, ok := ibanLengths[iban[:2]]
+		return ok && len(iban) == length && mod97(iban)
+	})
+
+	// NINO: two prefix letters, excluding D, F, I, Q, U and V in either
+	// position, O in the second, and prefixes never issued, six digits, and
+	// a suffix letter A-D
+	ninoMatcher = newMatcher(`\b[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z] ?\d{2} ?\d{2} ?\d{2} ?[A-D]\b`, func(c string) bool {
+		switch c[:2] {
+		case "BG", "GB", "KN", "NK", "NT", "TN", "ZZ":
+			return false
+		}
+		return consistentSeparators(c, 5)
+	})
+
+	// SIN: nine digits in groups of three, not starting with 0 or 8, with a
+	// valid Luhn checksum
+	sinMatcher = newMatcher(`\b\d{3}[- ]?\d{3}[- ]?\d{3}\b`, func(c string) bool {
+		d := digits(c)
+		return consistentSeparators(c, 3) && d[0] != '0' && d[0] != '8' && luhn(d)
+	})
+
+	// EIN: a valid two digit campus prefix, a dash and seven digits
+	einMatcher = newMatcher(`\b\d{2}-\d{7}\b`, func(c string) bool {
+		return einPrefixes[c[:2]]
+	})
+
+	// ITIN: like an SSN, starting with 9 and with a group of 50-65, 70-88,
+	// 90-92 or 94-99
+	itinMatcher = newMatcher(`\b9\d{2}[- ]?\d{2}[- ]?\d{4}\b`, func(c string) bool {
+		d := digits(c)
+		group := int(d[3]-'0')*10 + int(d[4]-'0')
+		return consistentSeparators(c, 3) &&
+			(group >= 50 && group <= 65 || group >= 70 && group <= 88 ||
+				group >= 90 && group <= 92 || group >= 94)
+	})
+)
+
+// ibanLengths maps country codes to the length of their IBANs.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16,
+	"BG": 22, "BH": 22, "BR": 29, "CH": 21, "CR": 22, "CY": 28, "CZ": 24,
+	"DE": 22, "DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18,
+	"FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27,
+	"GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IS": 26, "IT": 27,
+	"JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MR": 27, "MT": 31,
+	"MU": 30, "NL": 18, "NO": 15, "PK": 24, "PL": 28, "PS": 29, "PT": 25,
+	"QA": 29, "RO": 24, "RS": 22, "SA": 24, "SE": 24, "SI": 19, "SK": 24,
+	"SM": 27, "TN": 24, "TR": 26, "UA": 29, "VG": 24, "XK": 20,
+}
+
+// einPrefixes holds the campus prefixes assigned by the IRS to EINs.
+var einPrefixes = func() map[string]bool {
+	prefixes := map[string]bool{}
+	for _, r := range [][2]int{
+		{1, 6}, {10, 16}, {20, 27}, {30, 48}, {50, 68}, {71, 77}, {80, 88}, {90, 95}, {98, 99},
+	} {
+		for p := r[0]; p <= r[1]; p++ {
+			prefixes[fmt.Sprintf("%02d", p)] = true
+		}
+	}
+	return prefixes
+}()
+
+// ibanSize returns the byte length of the prefix of the candidate holding as
+// many characters, not counting spaces, as an IBAN of its country, or the
+// length of the candidate if it is shorter or the country is unknown.
+func ibanSize(c string) int {
+	length, ok := ibanLengths[c[:2]]
+	if !ok {
+		return len(c)
+	}
+
+	n := 0
+	for i := 0; i < len(c); i++ {
+		if c[i] == ' ' {
+			continue
+		}
+		if n++; n == length {
+			return i + 1
+		}
+	}
+	return len(c)
+}
+
+// mod97 reports whether the IBAN passes the ISO 7064 mod-97 checksum, where
+// letters count as two digit numbers from 10 to 35.
+func mod97(iban string) bool {
+	rem := 0
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			rem = (rem*100 + int(r-'A'+10)) % 97
+		} else {
+			rem = (rem*10 + int(r-'0')) % 97
+		}
+	}
+	return rem == 1
+}
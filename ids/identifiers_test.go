@@ -0,0 +1,83 @@
+package ids
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMatchers(t *testing.T) {
+	var tests = []struct {
+		id       Identifier
+		input    string
+		expected []string
+	}{
+		{SSN, "SSN 123-45-6789 on file", []string{"123-45-6789"}},
+		{SSN, "123 45 6789, 123456789", []string{"123 45 6789", "123456789"}},
+		{SSN, "000-12-3456 666-12-3456 900-12-3456", nil},
+		{SSN, "123-00-4567 123-45-0000", nil},
+		{SSN, "123-456789 123 45-6789", nil},
+		{SSN, "ZIP 12345-6789, invoice 1234-56-789", nil},
+		{SSN, "1123-45-6789 123-45-67890", nil},
+
+		{IBAN, "IBAN: GB82 WEST 1234 5698 7654 32.", []string{"GB82 WEST 1234 5698 7654 32"}},
+		{IBAN, "DE89370400440532013000 NL91ABNA0417164300", []string{"DE89370400440532013000", "NL91ABNA0417164300"}},
+		{IBAN, "GB82 WEST 1234 5698 7654 33", nil},   // checksum
+		{IBAN, "GB82 WEST 1234 5698 7654", nil},      // length
+		{IBAN, "XX82 WEST 1234 5698 7654 32", nil},   // country
+		{IBAN, "gb82 west 1234 5698 7654 32", nil},   // case
+		{IBAN, "GB82 WEST 1234 5698 7654 3200", nil}, // length
+		{IBAN, "ref AB12 CDEF GHIJ KLMN", nil},
+		{IBAN, "ES9121000418450200051332 2024-01-01", []string{"ES9121000418450200051332"}},
+		{IBAN, "BE68 5390 0754 7034 SENT", []string{"BE68 5390 0754 7034"}},
+		{IBAN, "ES9121000418450200051332 ES9121000418450200051332", []string{"ES9121000418450200051332", "ES9121000418450200051332"}},
+
+		{NINO, "NI number AB 12 34 56 C.", []string{"AB 12 34 56 C"}},
+		{NINO, "AB123456C", []string{"AB123456C"}},
+		{NINO, "QQ 12 34 56 C DA123456A AO123456A", nil},
+		{NINO, "GB123456A NK123456A TN123456A ZZ123456A", nil},
+		{NINO, "AB123456E AB 123456C", nil},
+
+		{SIN, "SIN 130-692-544", []string{"130-692-544"}},
+		{SIN, "130 692 544", []string{"130 692 544"}},
+		{SIN, "130-692-545", nil}, // checksum
+		{SIN, "130-692 544", nil}, // separators
+		{SIN, "046-454-286", nil}, // leading 0
+		{SIN, "800 000 008", nil}, // leading 8
+
+		{EIN, "EIN 12-3456789", []string{"12-3456789"}},
+		{EIN, "07-3456789 89-3456789 00-1234567", nil},
+		{EIN, "123456789 2023-1234567", nil},
+
+		{ITIN, "ITIN 912-70-1234 and 999 94 1234", []string{"912-70-1234", "999 94 1234"}},
+		{ITIN, "912-49-1234 912-66-1234 912-89-1234 912-93-1234", nil},
+		{ITIN, "123-70-1234", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("id=%s;input=%q", tt.id, tt.input), func(t *testing.T) {
+			var actual []string
+			for _, m := range tt.id.Matcher().FindAllIndex(tt.input, -1) {
+				actual = append(actual, tt.input[m[0]:m[1]])
+			}
+
+			if fmt.Sprintf("%q", actual) != fmt.Sprintf("%q", tt.expected) {
+				t.Errorf("Expected '%q', but got '%q'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMatchers_limit(t *testing.T) {
+	matcher := SSN.Matcher()
+	input := "000-12-3456 123-45-6789 234-56-7890"
+
+	if actual := matcher.FindAllIndex(input, 1); len(actual) != 1 || actual[0][0] != 12 {
+		t.Errorf("Expected '[[12 23]]', but got '%v'", actual)
+	}
+	if !matcher.Match(input) {
+		t.Error("Expected a match, but got none")
+	}
+	if matcher.Match("000-12-3456") {
+		t.Error("Expected no match, but got one")
+	}
+}
@@ -0,0 +1,120 @@
+package ids
+
+import (
+	"fmt"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/regex"
+)
+
+// Identifier is a kind of identifier recognized by the package.
+type Identifier int8
+
+const (
+	// SSN is a US Social Security number, such as 123-45-6789.
+	SSN Identifier = iota
+
+	// IBAN is an International Bank Account Number, such as
+	// GB82 WEST 1234 5698 7654 32.
+	IBAN
+
+	// NINO is a UK National Insurance number, such as AB 12 34 56 C.
+	NINO
+
+	// SIN is a Canadian Social Insurance number, such as 130-692-544.
+	SIN
+
+	// EIN is a US Employer Identification number, such as 12-3456789.
+	EIN
+
+	// ITIN is a US Individual Taxpayer Identification number, such as
+	// 912-70-1234.
+	ITIN
+)
+
+const errMsgFmtUnknownIdentifier = "ids.NewPair: unknown identifier %d"
+
+// All returns all the identifiers recognized by the package.
+func All() []Identifier {
+	return []Identifier{SSN, IBAN, NINO, SIN, EIN, ITIN}
+}
+
+// String returns the name of the identifier, which is also the name of its
+// pair.
+func (id Identifier) String() string {
+	switch id {
+	case SSN:
+		return "ssn"
+	case IBAN:
+		return "iban"
+	case NINO:
+		return "nino"
+	case SIN:
+		return "sin"
+	case EIN:
+		return "ein"
+	case ITIN:
+		return "itin"
+	default:
+		return fmt.Sprintf("Identifier(%d)", int8(id))
+	}
+}
+
+// Matcher returns the validating matcher for the identifier, or nil if the
+// identifier is unknown.
+func (id Identifier) Matcher() regex.Matcher {
+	switch id {
+	case SSN:
+		return ssnMatcher
+	case IBAN:
+		return ibanMatcher
+	case NINO:
+		return ninoMatcher
+	case SIN:
+		return sinMatcher
+	case EIN:
+		return einMatcher
+	case ITIN:
+		return itinMatcher
+	default:
+		return nil
+	}
+}
+
+// NewPair returns a new regex pair that redacts the valid matches of the
+// identifier with the specified redactor. The pair is named after the
+// identifier.
+func NewPair(id Identifier, redactor redact.Redactor) (*regex.Pair, error) {
+	matcher := id.Matcher()
+	if matcher == nil {
+		return nil, fmt.Errorf(errMsgFmtUnknownIdentifier, id)
+	}
+
+	pair, err := regex.NewPairWithMatcher(redactor, matcher)
+	if err != nil {
+		return nil, err
+	}
+
+	named := pair.Named(id.String())
+	return &named, nil
+}
+
+// New returns a new regex redactor that redacts the valid matches of the
+// specified identifiers, or of all identifiers if none are specified, with
+// the specified redactor.
+func New(redactor redact.Redactor, ids ...Identifier) (redact.Redactor, error) {
+	if len(ids) == 0 {
+		ids = All()
+	}
+
+	pairs := make([]regex.Pair, 0, len(ids))
+	for _, id := range ids {
+		pair, err := NewPair(id, redactor)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, *pair)
+	}
+
+	return regex.New(pairs)
+}
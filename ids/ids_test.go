@@ -0,0 +1,114 @@
+package ids
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/metrics"
+	"github.com/kristinjeanna/redact/regex"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func TestNew(t *testing.T) {
+	var tests = []struct {
+		ids      []Identifier
+		input    string
+		expected string
+	}{
+		{nil, "SSN 123-45-6789, ZIP 12345-6789", "SSN [id], ZIP 12345-6789"},
+		{nil, "IBAN GB82 WEST 1234 5698 7654 32, NINO AB123456C", "IBAN [id], NINO [id]"},
+		{nil, "SIN 130-692-544, EIN 12-3456789, ITIN 912-70-1234", "SIN [id], EIN [id], ITIN [id]"},
+		{[]Identifier{EIN}, "SSN 123-45-6789, EIN 12-3456789", "SSN 123-45-6789, EIN [id]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("ids=%v;input=%q", tt.ids, tt.input), func(t *testing.T) {
+			redactor, err := New(simple.New("[id]"), tt.ids...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNew_unknownIdentifier(t *testing.T) {
+	if _, err := New(simple.New("[id]"), SSN, Identifier(42)); err == nil {
+		t.Error("Expected error, but got nil")
+	}
+}
+
+func TestNewPair(t *testing.T) {
+	pair, err := NewPair(IBAN, simple.New("[iban]"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pair.Name() != "iban" {
+		t.Errorf("Expected 'iban', but got '%s'", pair.Name())
+	}
+
+	other, err := regex.NewPairUsingSimple("[token]", `token=\w+`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	counters := metrics.NewCounters()
+	redactor, err := regex.NewFromOptions([]regex.Pair{*pair, *other}, regex.WithHook(counters))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	actual, err := redactor.Redact("pay DE89 3704 0044 0532 0130 00 with token=abc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "pay [iban] with [token]"; actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+	if matches := counters.Get("iban").Matches; matches != 1 {
+		t.Errorf("Expected 1 match, but got %d", matches)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	redactor, err := New(simple.New("[id]"), SSN)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	spans, err := redactor.(redact.Detector).Detect("000-12-3456 and 123-45-6789")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []redact.Span{{Start: 16, End: 27, Replacement: "[id]"}}
+	if fmt.Sprint(spans) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%v', but got '%v'", expected, spans)
+	}
+}
+
+func TestIdentifier_String(t *testing.T) {
+	var tests = []struct {
+		id       Identifier
+		expected string
+	}{
+		{SSN, "ssn"}, {IBAN, "iban"}, {NINO, "nino"}, {SIN, "sin"}, {EIN, "ein"}, {ITIN, "itin"},
+		{Identifier(42), "Identifier(42)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if actual := tt.id.String(); actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
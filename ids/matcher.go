@@ -0,0 +1,128 @@
+package ids
+
+import (
+	"regexp"
+	"strings"
+)
+
+// validator reports whether the candidate match is a valid identifier.
+type validator func(candidate string) bool
+
+// sizer returns the byte length of the prefix of the candidate match that
+// forms the identifier.
+type sizer func(candidate string) int
+
+// validatingMatcher is a regex.Matcher that only reports the matches of a
+// regex that pass a validator. If a sizer is set, each match is first cut
+// to the size it returns.
+type validatingMatcher struct {
+	re    *regexp.Regexp
+	size  sizer
+	valid validator
+}
+
+func newMatcher(expr string, valid validator) validatingMatcher {
+	return validatingMatcher{re: regexp.MustCompile(expr), valid: valid}
+}
+
+func newSizedMatcher(expr string, size sizer, valid validator) validatingMatcher {
+	return validatingMatcher{re: regexp.MustCompile(expr), size: size, valid: valid}
+}
+
+// FindAllIndex returns the byte offsets of successive valid matches in s.
+// The regexes start and end with word boundaries, so resuming the search
+// at the end of a match, which is followed by a non-word character, sees
+// the same context as a search of all of s.
+func (m validatingMatcher) FindAllIndex(s string, n int) [][]int {
+	var matches [][]int
+	for pos := 0; n < 0 || len(matches) < n; {
+		loc := m.re.FindStringIndex(s[pos:])
+		if loc == nil {
+			break
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		pos = end
+
+		if m.size != nil {
+			cut := start + m.size(s[start:end])
+			if cut < end && isWordChar(s[cut]) {
+				continue // cut in the middle of a group
+			}
+			end = cut
+		}
+
+		if m.valid(s[start:end]) {
+			matches = append(matches, []int{start, end})
+			pos = end
+		}
+	}
+	return matches
+}
+
+// Match reports whether s contains any valid match.
+func (m validatingMatcher) Match(s string) bool {
+	return m.FindAllIndex(s, 1) != nil
+}
+
+// String returns the source text of the regex.
+func (m validatingMatcher) String() string {
+	return m.re.String()
+}
+
+// isWordChar reports whether c is an ASCII word character, as matched by \w.
+func isWordChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+// digits returns the digits in s.
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// consistentSeparators reports whether s, consisting of the specified
+// number of groups, either has no separators or has the same separator
+// between every group, as in 123-45-6789 but not 123-456789 or 123-45 6789.
+func consistentSeparators(s string, groups int) bool {
+	var seps []rune
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'A' && r <= 'Z') {
+			seps = append(seps, r)
+		}
+	}
+
+	if len(seps) == 0 {
+		return true
+	}
+	if len(seps) != groups-1 {
+		return false
+	}
+	for _, r := range seps {
+		if r != seps[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// luhn reports whether the digits pass the Luhn checksum.
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
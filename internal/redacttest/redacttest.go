@@ -0,0 +1,24 @@
+// Package redacttest provides redactors for testing the packages that wrap
+// other redactors.
+package redacttest
+
+import "errors"
+
+// ErrFailed is the error returned by Failing.
+var ErrFailed = errors.New("failed")
+
+// Failing is a redactor that always fails with ErrFailed.
+type Failing struct{}
+
+// Redact returns ErrFailed.
+func (Failing) Redact(string) (string, error) {
+	return "", ErrFailed
+}
+
+// Func is a redactor that calls the function to redact its input.
+type Func func(s string) (string, error)
+
+// Redact returns the result of calling f with s.
+func (f Func) Redact(s string) (string, error) {
+	return f(s)
+}
@@ -0,0 +1,4 @@
+// Package ip provides a redactor for IPv4 and IPv6 addresses found in text.
+// Addresses can be replaced, truncated to a subnet to retain coarse
+// geolocation, or pseudonymized with a keyed hash.
+package ip
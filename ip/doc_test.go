@@ -0,0 +1,21 @@
+package ip
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleNewFromOptions() {
+	redactor, err := NewFromOptions(WithMode(TruncateMode), WithSkipPrivate(true))
+	if err != nil {
+		log.Fatalf("an error occurred while creating redactor: %s", err)
+	}
+
+	result, err := redactor.Redact(`203.0.113.57 via 10.0.0.2 [2001:db8:85a3:8d3::7]:443 "GET / HTTP/1.1"`)
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+
+	fmt.Println(result)
+	// Output: 203.0.113.0 via 10.0.0.2 [2001:db8:85a3::]:443 "GET / HTTP/1.1"
+}
@@ -0,0 +1,323 @@
+package ip
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+// Mode determines how an IPRedactor replaces addresses.
+type Mode int8
+
+const (
+	// ReplaceMode replaces addresses with the output of a redactor.
+	ReplaceMode Mode = iota
+
+	// TruncateMode replaces addresses with the first address of their
+	// subnet, e.g. 192.0.2.0 for 192.0.2.17 with a /24 prefix.
+	TruncateMode
+
+	// HashMode replaces addresses with an address of the same family
+	// derived from a keyed hash, so that equal addresses map to equal
+	// pseudonyms.
+	HashMode
+)
+
+// String returns a text representation of the mode.
+func (m Mode) String() string {
+	switch m {
+	case TruncateMode:
+		return "TruncateMode"
+	case HashMode:
+		return "HashMode"
+	case ReplaceMode:
+		fallthrough
+	default:
+		return "ReplaceMode"
+	}
+}
+
+const (
+	defaultIPv4PrefixLength = 24
+	defaultIPv6PrefixLength = 48
+)
+
+var (
+	errRedactorNil = errors.New("ip.NewFromOptions: a redactor is required in ReplaceMode")
+	errKeyEmpty    = errors.New("ip.NewFromOptions: a key is required in HashMode")
+
+	errMsgFmtMode          = "ip.NewFromOptions: unknown mode %d"
+	errMsgFmtPrefixLength  = "ip.NewFromOptions: invalid prefix lengths /%d and /%d"
+	errMsgFmtRedactFailure = "ip.IPRedactor.Redact: error while redacting, %w"
+)
+
+// IPRedactor is a redactor that finds IPv4 and IPv6 addresses in text,
+// including bracketed and zone-scoped forms such as [fe80::1%eth0], and
+// replaces them according to its mode. The port of a host:port pair is left
+// untouched; IPv6 addresses must be bracketed to be followed by a port.
+type IPRedactor struct {
+	mode        Mode
+	redactor    redact.Redactor
+	ipv4Bits    int
+	ipv6Bits    int
+	key         []byte
+	skipPrivate bool
+}
+
+// New returns a new IPRedactor that replaces addresses with the output of
+// the specified redactor.
+func New(redactor redact.Redactor) redact.Redactor {
+	return IPRedactor{
+		redactor: redactor,
+		ipv4Bits: defaultIPv4PrefixLength,
+		ipv6Bits: defaultIPv6PrefixLength,
+	}
+}
+
+// NewFromOptions returns a new IPRedactor with the provided options.
+func NewFromOptions(opts ...Option) (redact.Redactor, error) {
+	r := New(nil).(IPRedactor)
+	for _, o := range opts {
+		o(&r)
+	}
+
+	switch r.mode {
+	case ReplaceMode:
+		if r.redactor == nil {
+			return nil, errRedactorNil
+		}
+	case TruncateMode:
+		if r.ipv4Bits < 0 || r.ipv4Bits > 32 || r.ipv6Bits < 0 || r.ipv6Bits > 128 {
+			return nil, fmt.Errorf(errMsgFmtPrefixLength, r.ipv4Bits, r.ipv6Bits)
+		}
+	case HashMode:
+		if len(r.key) == 0 {
+			return nil, errKeyEmpty
+		}
+	default:
+		return nil, fmt.Errorf(errMsgFmtMode, r.mode)
+	}
+
+	return r, nil
+}
+
+// Redact replaces the addresses in s.
+func (r IPRedactor) Redact(s string) (string, error) {
+	spans, err := r.Detect(s)
+	if err != nil {
+		return "", err
+	}
+
+	return redact.ApplySpans(s, spans), nil
+}
+
+// Detect returns the spans of the addresses in s that are replaced.
+func (r IPRedactor) Detect(s string) ([]redact.Span, error) {
+	var spans []redact.Span
+
+	for pos := 0; pos < len(s); {
+		start := pos
+		for start < len(s) && !isAddrByte(s[start]) {
+			start++
+		}
+		end := start
+		for end < len(s) && isAddrByte(s[end]) {
+			end++
+		}
+		if start == end {
+			break
+		}
+
+		// on failure, retry after the next separator, so that an address
+		// can follow a word, as in "host:192.0.2.1"
+		pos = end
+		if idx := strings.IndexAny(s[start:end], ".:"); idx >= 0 {
+			pos = start + idx + 1
+		}
+
+		addr, n, ok := parseAddr(s[start:end])
+		if !ok {
+			continue
+		}
+		end = start + n
+		if addr.Is6() && end < len(s) && s[end] == '%' {
+			addr, end = withZone(s, addr, end)
+		}
+
+		if start > 0 && isWordByte(s[start-1]) || end < len(s) && isWordByte(s[end]) {
+			continue
+		}
+		pos = end
+
+		if r.skipPrivate && isPrivate(addr) {
+			continue
+		}
+
+		repl, err := r.replace(addr, s[start:end])
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFmtRedactFailure, err)
+		}
+		spans = append(spans, redact.Span{Start: start, End: end, Replacement: repl})
+	}
+
+	return spans, nil
+}
+
+// parseAddr returns the address at the start of run, a maximal run of
+// address characters, and its length. The run may be followed by the port
+// of an IPv4 host:port pair or by punctuation ending a sentence.
+func parseAddr(run string) (netip.Addr, int, bool) {
+	if !strings.ContainsAny(run, ".:") {
+		return netip.Addr{}, 0, false
+	}
+
+	if addr, err := netip.ParseAddr(run); err == nil {
+		return addr, len(run), true
+	}
+
+	if idx := strings.LastIndexByte(run, ':'); idx > 0 && isPort(run[idx+1:]) {
+		if addr, err := netip.ParseAddr(run[:idx]); err == nil && addr.Is4() {
+			return addr, idx, true
+		}
+	}
+
+	trimmed := strings.TrimRight(run, ".:")
+	if addr, err := netip.ParseAddr(trimmed); err == nil && trimmed != "" {
+		return addr, len(trimmed), true
+	}
+
+	return netip.Addr{}, 0, false
+}
+
+// withZone returns the address with the zone following it at s[end:], which
+// starts with "%", and the end of the zone.
+func withZone(s string, addr netip.Addr, end int) (netip.Addr, int) {
+	zoneEnd := end + 1
+	for zoneEnd < len(s) && (isWordByte(s[zoneEnd]) || s[zoneEnd] == '-' || s[zoneEnd] == '.') {
+		zoneEnd++
+	}
+	if zoneEnd == end+1 {
+		return addr, end
+	}
+
+	return addr.WithZone(s[end+1 : zoneEnd]), zoneEnd
+}
+
+// replace returns the replacement for the address, whose text is text.
+func (r IPRedactor) replace(addr netip.Addr, text string) (string, error) {
+	switch r.mode {
+	case TruncateMode:
+		bits := r.ipv6Bits
+		if addr.Is4() {
+			bits = r.ipv4Bits
+		} else if addr.Is4In6() {
+			bits = 96 + r.ipv4Bits
+		}
+		prefix, _ := addr.WithZone("").Prefix(bits)
+		return prefix.Addr().String(), nil
+	case HashMode:
+		mac := hmac.New(sha256.New, r.key)
+		mac.Write(addr.WithZone("").AsSlice())
+		sum := mac.Sum(nil)
+		if addr.Is4() {
+			return netip.AddrFrom4([4]byte(sum[:4])).String(), nil
+		}
+		return netip.AddrFrom16([16]byte(sum[:16])).String(), nil
+	default:
+		return r.redactor.Redact(text)
+	}
+}
+
+// isPrivate reports whether the address is in a private, loopback,
+// link-local or unspecified range.
+func isPrivate(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsUnspecified()
+}
+
+// isPort reports whether s is a port number.
+func isPort(s string) bool {
+	if s == "" || len(s) > 5 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isAddrByte reports whether c may appear in the text of an address.
+func isAddrByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F' || c == '.' || c == ':'
+}
+
+// isWordByte reports whether c may appear in a word.
+func isWordByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+// String returns a text representation of the redactor.
+func (r IPRedactor) String() string {
+	return fmt.Sprintf("{mode=%q; redactor=%v; ipv4PrefixLength=%d; ipv6PrefixLength=%d; skipPrivate=%t}",
+		r.mode, r.redactor, r.ipv4Bits, r.ipv6Bits, r.skipPrivate)
+}
+
+// Option defines options for creating new IP redactors.
+type Option func(*IPRedactor)
+
+/*
+WithMode sets how addresses are replaced. Default is "ReplaceMode".
+*/
+func WithMode(mode Mode) Option {
+	return func(r *IPRedactor) {
+		r.mode = mode
+	}
+}
+
+/*
+WithRedactor sets the redactor whose output replaces addresses in
+ReplaceMode. Default is nil.
+*/
+func WithRedactor(redactor redact.Redactor) Option {
+	return func(r *IPRedactor) {
+		r.redactor = redactor
+	}
+}
+
+/*
+WithPrefixLengths sets the lengths of the IPv4 and IPv6 subnet prefixes that
+addresses are truncated to in TruncateMode. Default is /24 and /48.
+*/
+func WithPrefixLengths(ipv4, ipv6 int) Option {
+	return func(r *IPRedactor) {
+		r.ipv4Bits = ipv4
+		r.ipv6Bits = ipv6
+	}
+}
+
+/*
+WithKey sets the secret key of the hash in HashMode. Default is nil.
+*/
+func WithKey(key []byte) Option {
+	return func(r *IPRedactor) {
+		r.key = key
+	}
+}
+
+/*
+WithSkipPrivate sets whether addresses in private, loopback, link-local and
+unspecified ranges are left intact. Default is false.
+*/
+func WithSkipPrivate(skip bool) Option {
+	return func(r *IPRedactor) {
+		r.skipPrivate = skip
+	}
+}
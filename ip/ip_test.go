@@ -0,0 +1,260 @@
+package ip
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/internal/redacttest"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+const replacement = "[ip]"
+
+func TestRedact_ipv4(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"no addresses here", "no addresses here"},
+		{"192.0.2.17", "[ip]"},
+		{`203.0.113.9 - - [19/Oct/2026:10:00:00 +0000] "GET / HTTP/1.1" 200`, `[ip] - - [19/Oct/2026:10:00:00 +0000] "GET / HTTP/1.1" 200`},
+		{"connect to 192.0.2.17:8080 failed", "connect to [ip]:8080 failed"},
+		{"from 192.0.2.17.", "from [ip]."},
+		{"client=192.0.2.17,198.51.100.2", "client=[ip],[ip]"},
+		{"host:192.0.2.17", "host:[ip]"},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_ipv6(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"2001:db8::1", "[ip]"},
+		{"[2001:db8::1]:443", "[[ip]]:443"},
+		{"fe80::1%eth0 up", "[ip] up"},
+		{"[fe80::1%en0]:22", "[[ip]]:22"},
+		{"::ffff:192.0.2.17", "[ip]"},
+		{"::1 and ::", "[ip] and [ip]"},
+		{"2001:DB8:0:0:8:800:200C:417A", "[ip]"},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_lookalikes(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"at 12:30:45", "at 12:30:45"},
+		{"mac 00:1a:2b:3c:4d:5e", "mac 00:1a:2b:3c:4d:5e"},
+		{"version 1.2.3", "version 1.2.3"},
+		{"256.1.1.1", "256.1.1.1"},
+		{"v192.0.2.17", "v192.0.2.17"},
+		{"192.0.2.17x", "192.0.2.17x"},
+		{"deadbeef", "deadbeef"},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_redactorInput(t *testing.T) {
+	var seen []string
+	recorder := redacttest.Func(func(s string) (string, error) {
+		seen = append(seen, s)
+		return replacement, nil
+	})
+
+	if _, err := New(recorder).Redact("192.0.2.17:8080 [fe80::1%eth0]:22"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// ports are left out, zones are kept
+	if expected := []string{"192.0.2.17", "fe80::1%eth0"}; fmt.Sprint(seen) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%q', but got '%q'", expected, seen)
+	}
+
+	if _, err := New(redacttest.Failing{}).Redact("192.0.2.17"); !errors.Is(err, redacttest.ErrFailed) {
+		t.Errorf("Expected '%v', but got '%v'", redacttest.ErrFailed, err)
+	}
+}
+
+func TestRedact_truncate(t *testing.T) {
+	var tests = []struct {
+		opts     []Option
+		input    string
+		expected string
+	}{
+		{nil, "192.0.2.17:8080", "192.0.2.0:8080"},
+		{nil, "[2001:db8:1234:5678::1]:443", "[2001:db8:1234::]:443"},
+		{nil, "fe80::1%eth0", "fe80::"},
+		{nil, "::ffff:192.0.2.17", "::ffff:192.0.2.0"},
+		{[]Option{WithPrefixLengths(16, 32)}, "192.0.2.17 2001:db8:1234::1", "192.0.0.0 2001:db8::"},
+		{[]Option{WithSkipPrivate(true)}, "10.1.2.3 192.168.1.1 127.0.0.1 ::1 fe80::1 0.0.0.0 8.8.8.8", "10.1.2.3 192.168.1.1 127.0.0.1 ::1 fe80::1 0.0.0.0 8.8.8.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			redactor, err := NewFromOptions(append([]Option{WithMode(TruncateMode)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_hash(t *testing.T) {
+	redactor, err := NewFromOptions(WithMode(HashMode), WithKey([]byte("secret")))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	other, err := NewFromOptions(WithMode(HashMode), WithKey([]byte("other")))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, input := range []string{"192.0.2.17", "2001:db8::1"} {
+		t.Run(fmt.Sprintf("input=%q", input), func(t *testing.T) {
+			first, err := redactor.Redact(input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			second, _ := redactor.Redact(input)
+			third, _ := other.Redact(input)
+
+			if first != second {
+				t.Errorf("Expected '%s', but got '%s'", first, second)
+			}
+			if first == input || first == third {
+				t.Errorf("Expected a keyed pseudonym, but got '%s' and '%s'", first, third)
+			}
+
+			addr, err := netip.ParseAddr(first)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if addr.Is4() != netip.MustParseAddr(input).Is4() {
+				t.Errorf("Expected the address family of '%s', but got '%s'", input, first)
+			}
+		})
+	}
+}
+
+func TestRedact_hashIgnoresZone(t *testing.T) {
+	redactor, err := NewFromOptions(WithMode(HashMode), WithKey([]byte("secret")))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	actual, err := redactor.Redact("fe80::1%eth0 fe80::1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	first, second, _ := strings.Cut(actual, " ")
+	if first != second {
+		t.Errorf("Expected '%s', but got '%s'", first, second)
+	}
+}
+
+func TestDetect_port(t *testing.T) {
+	spans, err := New(simple.New(replacement)).(redact.Detector).Detect("GET from 192.0.2.17:80")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []redact.Span{{Start: 9, End: 19, Replacement: replacement}}
+	if fmt.Sprint(spans) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%v', but got '%v'", expected, spans)
+	}
+}
+
+func TestNewFromOptions_errors(t *testing.T) {
+	var tests = []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{"no redactor", nil, errRedactorNil.Error()},
+		{"no key", []Option{WithMode(HashMode)}, errKeyEmpty.Error()},
+		{"unknown mode", []Option{WithMode(3)}, fmt.Sprintf(errMsgFmtMode, 3)},
+		{"IPv4 prefix too long", []Option{WithMode(TruncateMode), WithPrefixLengths(33, 48)}, fmt.Sprintf(errMsgFmtPrefixLength, 33, 48)},
+		{"IPv6 prefix negative", []Option{WithMode(TruncateMode), WithPrefixLengths(24, -1)}, fmt.Sprintf(errMsgFmtPrefixLength, 24, -1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFromOptions(tt.opts...)
+			if err == nil {
+				t.Fatal("Expected error, but got nil")
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	redactor, err := NewFromOptions(WithMode(TruncateMode), WithSkipPrivate(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{mode="TruncateMode"; redactor=<nil>; ipv4PrefixLength=24; ipv6PrefixLength=48; skipPrivate=true}`
+	if actual := fmt.Sprint(redactor); actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
@@ -0,0 +1,4 @@
+// Package jwt provides a redactor for JSON Web Tokens found in text. Tokens
+// are decoded and re-emitted in a form that keeps their non-sensitive parts
+// readable for debugging, but that is never usable as a credential.
+package jwt
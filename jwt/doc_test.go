@@ -0,0 +1,26 @@
+package jwt
+
+import (
+	"fmt"
+	"log"
+)
+
+func ExampleNewFromOptions() {
+	redactor, err := NewFromOptions(WithForm(Summarize))
+	if err != nil {
+		log.Fatalf("an error occurred while creating redactor: %s", err)
+	}
+
+	// header {"alg":"HS256","typ":"JWT"}, payload {"iss":"auth","sub":"bob","exp":1700000000}
+	token := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9." +
+		"eyJpc3MiOiJhdXRoIiwic3ViIjoiYm9iIiwiZXhwIjoxNzAwMDAwMDAwfQ." +
+		"c2lnbmF0dXJl"
+
+	result, err := redactor.Redact("Authorization: Bearer " + token)
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+
+	fmt.Println(result)
+	// Output: Authorization: Bearer jwt{alg=HS256,iss=auth,exp=1700000000}
+}
@@ -0,0 +1,386 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+// Form determines how a JWTRedactor re-emits the tokens it finds.
+type Form int8
+
+const (
+	// DropSignature keeps the header and the payload of each token but
+	// drops its signature. Encrypted tokens keep their header only.
+	DropSignature Form = iota
+
+	// Summarize replaces each token with a summary of its header and
+	// selected claims, such as "jwt{alg=RS256,iss=issuer,exp=1700000000}".
+	Summarize
+)
+
+// String returns a text representation of the form.
+func (f Form) String() string {
+	switch f {
+	case Summarize:
+		return "Summarize"
+	case DropSignature:
+		fallthrough
+	default:
+		return "DropSignature"
+	}
+}
+
+var (
+	errClaimRedactorNil = errors.New("jwt.WithClaims: redactor must not be nil")
+
+	errMsgFmtForm          = "jwt.NewFromOptions: unknown form %d"
+	errMsgFmtRedactFailure = "jwt.JWTRedactor.Redact: error while redacting, %w"
+)
+
+// tokenRegex matches candidate compact tokens. The header of a token is a
+// JSON object and so always starts with `{"`, encoded as "eyJ".
+var tokenRegex = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*(?:\.[A-Za-z0-9_-]*){2}(?:(?:\.[A-Za-z0-9_-]*){2})?`)
+
+// DefaultClaims returns the claims redacted by WithClaims when none are
+// specified.
+func DefaultClaims() []string {
+	return []string{"sub", "email"}
+}
+
+// DefaultSummaryClaims returns the claims included in summaries by default.
+func DefaultSummaryClaims() []string {
+	return []string{"iss", "aud", "exp"}
+}
+
+// JWTRedactor is a redactor that finds compact JSON Web Tokens, both signed
+// (JWS) and encrypted (JWE), in text and re-emits them in the configured
+// form. Tokens are recognized by a header that decodes to a JSON object with
+// an "alg" parameter. The values of the listed claims in the payload of a
+// signed token are replaced by the output of a redactor, in every form.
+type JWTRedactor struct {
+	form          Form
+	claimRedactor redact.Redactor
+	claims        map[string]bool
+	summaryClaims []string
+	err           error
+}
+
+// New returns a new JWTRedactor that drops the signatures of tokens.
+func New() redact.Redactor {
+	return JWTRedactor{summaryClaims: DefaultSummaryClaims()}
+}
+
+// NewFromOptions returns a new JWTRedactor with the provided options.
+func NewFromOptions(opts ...Option) (redact.Redactor, error) {
+	r := JWTRedactor{summaryClaims: DefaultSummaryClaims()}
+	for _, o := range opts {
+		o(&r)
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.form < DropSignature || r.form > Summarize {
+		return nil, fmt.Errorf(errMsgFmtForm, r.form)
+	}
+
+	return r, nil
+}
+
+// Redact re-emits the tokens in s in the configured form.
+func (r JWTRedactor) Redact(s string) (string, error) {
+	spans, err := r.Detect(s)
+	if err != nil {
+		return "", err
+	}
+
+	return redact.ApplySpans(s, spans), nil
+}
+
+// Detect returns the spans of the tokens in s.
+func (r JWTRedactor) Detect(s string) ([]redact.Span, error) {
+	var spans []redact.Span
+
+	for _, m := range tokenRegex.FindAllStringIndex(s, -1) {
+		if m[0] > 0 && isTokenByte(s[m[0]-1]) {
+			continue // part of a longer word
+		}
+
+		t, ok := parseToken(s[m[0]:m[1]])
+		if !ok {
+			continue
+		}
+
+		repl, err := r.emit(t)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFmtRedactFailure, err)
+		}
+
+		spans = append(spans, redact.Span{Start: m[0], End: m[0] + t.length, Replacement: repl})
+	}
+
+	return spans, nil
+}
+
+// String returns a text representation of the redactor.
+func (r JWTRedactor) String() string {
+	claims := make([]string, 0, len(r.claims))
+	for c := range r.claims {
+		claims = append(claims, c)
+	}
+	sort.Strings(claims)
+
+	return fmt.Sprintf("{form=%q; claims=%q; claimRedactor=%v; summaryClaims=%q}",
+		r.form, claims, r.claimRedactor, r.summaryClaims)
+}
+
+// token is a decoded compact token.
+type token struct {
+	length    int    // length of the token text
+	header    string // encoded header
+	encrypted bool
+	params    []member // decoded header
+	claims    []member // decoded payload, nil if not a JSON object
+	payload   string   // encoded payload
+}
+
+// member is a member of a JSON object.
+type member struct {
+	name  string
+	value json.RawMessage
+}
+
+// parseToken decodes the compact token at the start of text, which may
+// contain trailing segments that are not part of it.
+func parseToken(text string) (token, bool) {
+	parts := strings.Split(text, ".")
+
+	params, ok := decodeObject(parts[0])
+	if !ok || !hasString(params, "alg") {
+		return token{}, false
+	}
+
+	t := token{header: parts[0], params: params}
+	if len(parts) == 5 && hasString(params, "enc") {
+		t.encrypted = true
+		t.length = len(text)
+		return t, true
+	}
+
+	t.length = len(strings.Join(parts[:3], "."))
+	t.payload = parts[1]
+	t.claims, _ = decodeObject(parts[1])
+	return t, true
+}
+
+// emit returns the replacement for the token in the configured form.
+func (r JWTRedactor) emit(t token) (string, error) {
+	claims, err := r.redactClaims(t.claims)
+	if err != nil {
+		return "", err
+	}
+
+	if r.form == Summarize {
+		return r.summarize(t, claims), nil
+	}
+
+	if t.encrypted {
+		return t.header + "....", nil
+	}
+
+	payload := t.payload
+	if claims != nil && r.claimRedactor != nil {
+		payload = base64.RawURLEncoding.EncodeToString(encodeObject(claims))
+	}
+	return t.header + "." + payload + ".", nil
+}
+
+// redactClaims returns the claims with the values of the listed claims
+// replaced.
+func (r JWTRedactor) redactClaims(claims []member) ([]member, error) {
+	if r.claimRedactor == nil || claims == nil {
+		return claims, nil
+	}
+
+	redacted := make([]member, len(claims))
+	for i, c := range claims {
+		redacted[i] = c
+		if !r.claims[c.name] {
+			continue
+		}
+
+		out, err := r.claimRedactor.Redact(valueText(c.value))
+		if err != nil {
+			return nil, err
+		}
+		redacted[i].value, _ = json.Marshal(out)
+	}
+
+	return redacted, nil
+}
+
+// summarize returns the summary of the token with the specified claims.
+func (r JWTRedactor) summarize(t token, claims []member) string {
+	var fields []string
+	for _, name := range []string{"alg", "enc"} {
+		if v, ok := lookup(t.params, name); ok {
+			fields = append(fields, name+"="+valueText(v))
+		}
+	}
+	for _, name := range r.summaryClaims {
+		if v, ok := lookup(claims, name); ok {
+			fields = append(fields, name+"="+valueText(v))
+		}
+	}
+
+	return "jwt{" + strings.Join(fields, ",") + "}"
+}
+
+// decodeObject decodes the base64url encoded JSON object in s, keeping the
+// order of its members.
+func decodeObject(s string) ([]member, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+
+	members := []member{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false
+		}
+		members = append(members, member{name: tok.(string), value: value})
+	}
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('}') {
+		return nil, false
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, false // trailing data
+	}
+
+	return members, true
+}
+
+// encodeObject encodes the members as a compact JSON object.
+func encodeObject(members []member) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(m.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		_ = json.Compact(&buf, m.value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// lookup returns the value of the named member.
+func lookup(members []member, name string) (json.RawMessage, bool) {
+	for _, m := range members {
+		if m.name == name {
+			return m.value, true
+		}
+	}
+	return nil, false
+}
+
+// hasString reports whether the named member is a string.
+func hasString(members []member, name string) bool {
+	v, ok := lookup(members, name)
+	var s string
+	return ok && json.Unmarshal(v, &s) == nil
+}
+
+// valueText returns the text of a string value, or the compact JSON text of
+// any other value.
+func valueText(v json.RawMessage) string {
+	var s string
+	if json.Unmarshal(v, &s) == nil {
+		return s
+	}
+
+	var buf bytes.Buffer
+	if json.Compact(&buf, v) != nil {
+		return string(v)
+	}
+	return buf.String()
+}
+
+// isTokenByte reports whether c may appear in a compact token.
+func isTokenByte(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '_' || c == '.'
+}
+
+// Option defines options for creating new JWT redactors.
+type Option func(*JWTRedactor)
+
+/*
+WithForm sets the form in which tokens are re-emitted. Default is
+"DropSignature".
+*/
+func WithForm(form Form) Option {
+	return func(r *JWTRedactor) {
+		r.form = form
+	}
+}
+
+/*
+WithClaims sets the redactor whose output replaces the values of the
+specified claims. Values that are not strings are passed to the redactor as
+JSON text, and the output always replaces them as a string. If no claims are
+specified, those returned by DefaultClaims are used. Default is no redacted
+claims.
+*/
+func WithClaims(redactor redact.Redactor, claims ...string) Option {
+	return func(r *JWTRedactor) {
+		if redactor == nil {
+			r.err = errClaimRedactorNil
+			return
+		}
+		if len(claims) == 0 {
+			claims = DefaultClaims()
+		}
+
+		r.claimRedactor = redactor
+		r.claims = make(map[string]bool, len(claims))
+		for _, c := range claims {
+			r.claims[c] = true
+		}
+	}
+}
+
+/*
+WithSummaryClaims sets the claims included in summaries, after the "alg" and
+"enc" header parameters. Default is the claims returned by
+DefaultSummaryClaims.
+*/
+func WithSummaryClaims(claims ...string) Option {
+	return func(r *JWTRedactor) {
+		r.summaryClaims = claims
+	}
+}
@@ -0,0 +1,268 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/internal/redacttest"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+const (
+	header  = `{"alg":"RS256","typ":"JWT"}`
+	payload = `{"iss":"https://auth.example.com","sub":"bob","email":"bob@example.com","exp":1700000000,"roles":["admin"]}`
+)
+
+func encode(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+var (
+	encHeader  = encode(header)
+	encPayload = encode(payload)
+	signed     = encHeader + "." + encPayload + ".c2lnbmF0dXJl"
+	encrypted  = encode(`{"alg":"RSA-OAEP","enc":"A256GCM"}`) + ".a2V5.aXY.Y2lwaGVy.dGFn"
+)
+
+func TestRedact_signatures(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"no tokens here", "no tokens here"},
+		{signed, encHeader + "." + encPayload + "."},
+		{"Authorization: Bearer " + signed + "\n", "Authorization: Bearer " + encHeader + "." + encPayload + ".\n"},
+		{`{"token":"` + signed + `"}`, `{"token":"` + encHeader + "." + encPayload + `."}`},
+		{"token " + signed + ".", "token " + encHeader + "." + encPayload + ".."},
+		{encHeader + "." + encPayload + ".", encHeader + "." + encPayload + "."},
+		{encHeader + "." + encode("not json") + ".c2ln", encHeader + "." + encode("not json") + "."},
+		{signed + ".eA.eA", encHeader + "." + encPayload + "..eA.eA"},
+	}
+
+	redactor := New()
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_encrypted(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{encrypted, encode(`{"alg":"RSA-OAEP","enc":"A256GCM"}`) + "...."},
+		{"jwe=" + encrypted + ";", "jwe=" + encode(`{"alg":"RSA-OAEP","enc":"A256GCM"}`) + "....;"},
+	}
+
+	redactor := New()
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_notTokens(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"x" + signed, "x" + signed},
+		{encode(`{"typ":"JWT"}`) + ".e30.c2ln", encode(`{"typ":"JWT"}`) + ".e30.c2ln"},
+		{encode(`{"alg":1}`) + ".e30.c2ln", encode(`{"alg":1}`) + ".e30.c2ln"},
+		{encode(`{"alg":"none"} x`) + ".e30.c2ln", encode(`{"alg":"none"} x`) + ".e30.c2ln"},
+		{"eyJ!.a.b", "eyJ!.a.b"},
+	}
+
+	redactor := New()
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithClaims(t *testing.T) {
+	var tests = []struct {
+		name     string
+		opt      Option
+		input    string
+		expected string
+	}{
+		{
+			"default claims",
+			WithClaims(simple.New("[redacted]")),
+			signed,
+			encHeader + "." + encode(`{"iss":"https://auth.example.com","sub":"[redacted]","email":"[redacted]","exp":1700000000,"roles":["admin"]}`) + ".",
+		},
+		{
+			"non-string claims",
+			WithClaims(simple.New("x"), "exp", "roles"),
+			signed,
+			encHeader + "." + encode(`{"iss":"https://auth.example.com","sub":"bob","email":"bob@example.com","exp":"x","roles":"x"}`) + ".",
+		},
+		{
+			"encrypted token",
+			WithClaims(simple.New("[redacted]")),
+			encrypted,
+			encode(`{"alg":"RSA-OAEP","enc":"A256GCM"}`) + "....",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redactor, err := NewFromOptions(tt.opt)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithClaims_redactorInput(t *testing.T) {
+	var seen []string
+	recorder := redacttest.Func(func(s string) (string, error) {
+		seen = append(seen, s)
+		return "x", nil
+	})
+
+	redactor, err := NewFromOptions(WithClaims(recorder, "sub", "exp", "roles"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := redactor.Redact(signed + " " + encrypted); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// strings are unquoted, other values are passed as JSON text, and the
+	// claims of encrypted tokens are never read
+	if expected := []string{"bob", "1700000000", `["admin"]`}; fmt.Sprint(seen) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%q', but got '%q'", expected, seen)
+	}
+
+	failing, err := NewFromOptions(WithClaims(redacttest.Failing{}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := failing.Redact(signed); !errors.Is(err, redacttest.ErrFailed) {
+		t.Errorf("Expected '%v', but got '%v'", redacttest.ErrFailed, err)
+	}
+}
+
+func TestWithForm_summarize(t *testing.T) {
+	var tests = []struct {
+		opts     []Option
+		input    string
+		expected string
+	}{
+		{nil, "token=" + signed, "token=jwt{alg=RS256,iss=https://auth.example.com,exp=1700000000}"},
+		{
+			[]Option{WithSummaryClaims("sub", "roles", "missing"), WithClaims(simple.New("[redacted]"), "sub")},
+			signed,
+			`jwt{alg=RS256,sub=[redacted],roles=["admin"]}`,
+		},
+		{nil, encrypted, "jwt{alg=RSA-OAEP,enc=A256GCM}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			redactor, err := NewFromOptions(append([]Option{WithForm(Summarize)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDetect_bearer(t *testing.T) {
+	input := "Bearer " + signed
+
+	spans, err := New().(redact.Detector).Detect(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []redact.Span{{Start: 7, End: len(input), Replacement: encHeader + "." + encPayload + "."}}
+	if fmt.Sprint(spans) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%v', but got '%v'", expected, spans)
+	}
+}
+
+func TestNewFromOptions_errors(t *testing.T) {
+	var tests = []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{"nil claim redactor", []Option{WithClaims(nil)}, errClaimRedactorNil.Error()},
+		{"unknown form", []Option{WithForm(2)}, fmt.Sprintf(errMsgFmtForm, 2)},
+		{"negative form", []Option{WithForm(-1)}, fmt.Sprintf(errMsgFmtForm, -1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFromOptions(tt.opts...)
+			if err == nil {
+				t.Fatal("Expected error, but got nil")
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	redactor, err := NewFromOptions(WithForm(Summarize), WithClaims(simple.New("x"), "sub", "email"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{form="Summarize"; claims=["email" "sub"]; claimRedactor={replacement="x"}; summaryClaims=["iss" "aud" "exp"]}`
+	if actual := fmt.Sprint(redactor); actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
@@ -0,0 +1,6 @@
+// Package keyword provides a redactor for values that follow trigger
+// keywords in free text, such as "password: hunter2", "api key is abc123" or
+// `"secret":"..."`. It replaces hand-tuned patterns, such as
+// regex.AuthHeaderRegex, for the many secrets that only look like secrets
+// because of the word before them.
+package keyword
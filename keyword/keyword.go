@@ -0,0 +1,439 @@
+package keyword
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+const (
+	defaultQuotes = "\"'`"
+	escape        = '\\'
+
+	// valueTerminators end an unquoted value, in addition to whitespace and
+	// quotes
+	valueTerminators = ",;&)]}<>"
+)
+
+var (
+	errRedactorNil     = errors.New("keyword.NewFromOptions: redactor must not be nil")
+	errKeywordsEmpty   = errors.New("keyword.NewFromOptions: at least one keyword is required")
+	errSeparatorsEmpty = errors.New("keyword.NewFromOptions: at least one separator is required")
+
+	errMsgFmtRedactFailure = "keyword.KeywordRedactor.Redact: error while redacting, %w"
+)
+
+// DefaultKeywords returns the keywords whose values are redacted by default.
+func DefaultKeywords() []string {
+	return []string{
+		"password",
+		"passwd",
+		"pwd",
+		"passphrase",
+		"secret",
+		"client secret",
+		"token",
+		"access token",
+		"refresh token",
+		"api key",
+		"apikey",
+		"access key",
+		"private key",
+		"authorization",
+		"credentials",
+	}
+}
+
+// DefaultSeparators returns the separators between keywords and values used
+// by default.
+func DefaultSeparators() []string {
+	return []string{":", "=", ":=", "=>", "is"}
+}
+
+// authSchemes are the HTTP authentication schemes whose credentials, rather
+// than the scheme itself, are redacted.
+var authSchemes = []string{"basic", "bearer", "digest", "token", "negotiate"}
+
+// KeywordRedactor is a redactor that replaces the value following any of a
+// set of trigger keywords with the output of another redactor.
+//
+// Keywords match case-insensitively, at the start of a word or after a
+// separator like "_" or ".", so that "password" also matches the key
+// "db_password". A space in a keyword matches any run of spaces, tabs, "_"
+// and "-", so that "api key" also matches "API_KEY" and "api-key". The
+// keyword may be quoted, as a JSON key, and must be followed by one of the
+// separators, such as ":", "=" or "is", and then the value.
+//
+// A quoted value extends to its closing quote, or to the end of the line,
+// and only its content is replaced, with the quote and escape characters of
+// the output escaped. Keywords and values may also be quoted with escaped
+// quotes, as in JSON logged within a JSON string, in which case the output
+// is escaped twice. An unquoted value extends to the next whitespace or
+// one of the characters ,;&)]}<> or a quote, excluding a trailing period.
+// For values starting with an HTTP authentication scheme, such as "Bearer",
+// the credentials following the scheme are replaced.
+type KeywordRedactor struct {
+	redactor   redact.Redactor
+	keywords   []string
+	separators []string
+	quotes     string
+}
+
+// New returns a new KeywordRedactor that replaces the values of the default
+// keywords with the output of the redactor.
+func New(redactor redact.Redactor) redact.Redactor {
+	return KeywordRedactor{
+		redactor:   redactor,
+		keywords:   normalize(DefaultKeywords()),
+		separators: normalize(DefaultSeparators()),
+		quotes:     defaultQuotes,
+	}
+}
+
+// NewFromOptions returns a new KeywordRedactor with the provided options.
+func NewFromOptions(redactor redact.Redactor, opts ...Option) (redact.Redactor, error) {
+	if redactor == nil {
+		return nil, errRedactorNil
+	}
+
+	r := New(redactor).(KeywordRedactor)
+	for _, o := range opts {
+		o(&r)
+	}
+
+	if len(r.keywords) == 0 {
+		return nil, errKeywordsEmpty
+	}
+	if len(r.separators) == 0 {
+		return nil, errSeparatorsEmpty
+	}
+
+	return r, nil
+}
+
+// normalize returns the lowercased, non-empty values, longest first, so that
+// the longest of several matching values wins.
+func normalize(values []string) []string {
+	normalized := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			normalized = append(normalized, v)
+		}
+	}
+
+	sort.SliceStable(normalized, func(i, j int) bool {
+		return len(normalized[i]) > len(normalized[j])
+	})
+	return normalized
+}
+
+// Redact replaces the values following the keywords in s.
+func (r KeywordRedactor) Redact(s string) (string, error) {
+	spans, err := r.Detect(s)
+	if err != nil {
+		return "", err
+	}
+
+	return redact.ApplySpans(s, spans), nil
+}
+
+// Detect returns the spans of the values following the keywords in s.
+func (r KeywordRedactor) Detect(s string) ([]redact.Span, error) {
+	var spans []redact.Span
+
+	for i := 0; i < len(s); i++ {
+		if i > 0 && isAlnum(s[i-1]) {
+			continue // not at the start of a word
+		}
+
+		end, ok := r.matchKeyword(s, i)
+		if !ok {
+			continue
+		}
+		if end, ok = r.matchSeparator(s, end); !ok {
+			continue
+		}
+
+		v, ok := r.scanValue(s, end)
+		if !ok {
+			continue
+		}
+
+		repl, err := r.redactor.Redact(v.value)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFmtRedactFailure, err)
+		}
+		if v.quote != 0 {
+			repl = escapeQuoted(repl, v.quote)
+		}
+		if v.escaped {
+			repl = escapeQuoted(repl, v.quote)
+		}
+
+		spans = append(spans, redact.Span{Start: v.start, End: v.end, Replacement: repl})
+		i = v.end - 1
+	}
+
+	return spans, nil
+}
+
+// matchKeyword returns the end of the keyword starting at byte offset i of
+// s, including a closing quote, escaped or not, if the keyword is quoted.
+func (r KeywordRedactor) matchKeyword(s string, i int) (int, bool) {
+	for _, k := range r.keywords {
+		end, ok := matchWords(s, i, k)
+		if !ok || end < len(s) && isAlnum(s[end]) {
+			continue
+		}
+
+		if i > 0 && end < len(s) && strings.IndexByte(r.quotes, s[i-1]) >= 0 {
+			switch q := s[i-1]; {
+			case s[end] == q:
+				end++ // closing quote of a quoted keyword
+			case i > 1 && s[i-2] == escape && s[end] == escape && end+1 < len(s) && s[end+1] == q:
+				end += 2 // escaped closing quote, as in JSON within a string
+			}
+		}
+		return end, true
+	}
+
+	return 0, false
+}
+
+// matchWords matches the keyword k at byte offset i of s, ignoring case, and
+// returns the end of the match.
+func matchWords(s string, i int, k string) (int, bool) {
+	for j := 0; j < len(k); j++ {
+		if k[j] == ' ' {
+			start := i
+			for i < len(s) && strings.IndexByte(" \t_-", s[i]) >= 0 {
+				i++
+			}
+			if i == start {
+				return 0, false
+			}
+			continue
+		}
+
+		if i == len(s) || toLower(s[i]) != k[j] {
+			return 0, false
+		}
+		i++
+	}
+
+	return i, true
+}
+
+// matchSeparator returns the start of the value following a separator at
+// byte offset i of s, skipping whitespace around the separator.
+func (r KeywordRedactor) matchSeparator(s string, i int) (int, bool) {
+	i = skipBlanks(s, i)
+
+	for _, sep := range r.separators {
+		end, ok := matchWords(s, i, sep)
+		if !ok {
+			continue
+		}
+		if isAlnum(sep[len(sep)-1]) && (i == 0 || isAlnum(s[i-1]) || end < len(s) && isAlnum(s[end])) {
+			continue // word separators, such as "is", must be whole words
+		}
+		return skipBlanks(s, end), true
+	}
+
+	return 0, false
+}
+
+// value is a value scanned from the input.
+type value struct {
+	start, end int    // byte offsets of the replaced text
+	value      string // the value, unescaped if it is quoted
+	quote      byte   // the opening quote, or 0 if the value is not quoted
+	escaped    bool   // whether the quotes are escaped
+}
+
+// scanValue scans the value starting at byte offset i of s.
+func (r KeywordRedactor) scanValue(s string, i int) (value, bool) {
+	if i == len(s) || s[i] == '\n' || s[i] == '\r' {
+		return value{}, false
+	}
+
+	if strings.IndexByte(r.quotes, s[i]) >= 0 {
+		return scanQuoted(s, i), true
+	}
+	if s[i] == escape && i+1 < len(s) && strings.IndexByte(r.quotes, s[i+1]) >= 0 {
+		return scanEscapedQuoted(s, i), true
+	}
+
+	end := i
+	for end < len(s) && !isSpace(s[end]) && strings.IndexByte(valueTerminators, s[end]) < 0 &&
+		strings.IndexByte(r.quotes, s[end]) < 0 {
+		end++
+	}
+	for end > i && s[end-1] == '.' && (end == len(s) || isSpace(s[end])) {
+		end-- // period ending a sentence
+	}
+	if end == i {
+		return value{}, false
+	}
+
+	// credentials following an authentication scheme
+	if isAuthScheme(s[i:end]) && end < len(s) && (s[end] == ' ' || s[end] == '\t') {
+		if v, ok := r.scanValue(s, skipBlanks(s, end)); ok && v.quote == 0 {
+			return v, true
+		}
+	}
+
+	return value{start: i, end: end, value: s[i:end]}, true
+}
+
+// scanQuoted scans the quoted value starting at byte offset i of s. The
+// replaced text excludes the quotes.
+func scanQuoted(s string, i int) value {
+	v := value{start: i + 1, quote: s[i]}
+
+	var b strings.Builder
+	j := i + 1
+	for ; j < len(s); j++ {
+		c := s[j]
+		if c == v.quote || c == '\n' || c == '\r' {
+			break
+		}
+		if c == escape && j+1 < len(s) && (s[j+1] == v.quote || s[j+1] == escape) {
+			j++
+			c = s[j]
+		}
+		b.WriteByte(c)
+	}
+
+	v.end, v.value = j, b.String()
+	return v
+}
+
+// scanEscapedQuoted scans the value starting at byte offset i of s, whose
+// quotes are escaped, as in JSON within a JSON string. The value is
+// unescaped twice, and the replaced text excludes the escaped quotes.
+func scanEscapedQuoted(s string, i int) value {
+	v := value{start: i + 2, quote: s[i+1], escaped: true}
+
+	// next returns the character at byte offset j of s, unescaping the
+	// escaped quote and escape characters, and its length
+	next := func(j int) (byte, int) {
+		if s[j] == escape && j+1 < len(s) && (s[j+1] == v.quote || s[j+1] == escape) {
+			return s[j+1], 2
+		}
+		return s[j], 1
+	}
+
+	var b strings.Builder
+	j := v.start
+	for j < len(s) && s[j] != '\n' && s[j] != '\r' {
+		c, n := next(j)
+		if c == v.quote {
+			break // escaped closing quote, or the end of the enclosing string
+		}
+		if c == escape && j+n < len(s) {
+			if c2, n2 := next(j + n); c2 == v.quote || c2 == escape {
+				c, n = c2, n+n2
+			}
+		}
+		b.WriteByte(c)
+		j += n
+	}
+
+	v.end, v.value = j, b.String()
+	return v
+}
+
+// escapeQuoted escapes the quote and escape characters in the output for a
+// value quoted with quote.
+func escapeQuoted(out string, quote byte) string {
+	if strings.IndexByte(out, quote) < 0 && strings.IndexByte(out, escape) < 0 {
+		return out
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(out); i++ {
+		if out[i] == quote || out[i] == escape {
+			b.WriteByte(escape)
+		}
+		b.WriteByte(out[i])
+	}
+	return b.String()
+}
+
+// isAuthScheme reports whether s is an HTTP authentication scheme.
+func isAuthScheme(s string) bool {
+	for _, scheme := range authSchemes {
+		if strings.EqualFold(s, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipBlanks returns the offset of the first byte at or after i in s that is
+// not a space or tab.
+func skipBlanks(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+func isAlnum(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func toLower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// String returns a text representation of the redactor.
+func (r KeywordRedactor) String() string {
+	return fmt.Sprintf("{keywords=%q; separators=%q; quotes=%q; redactor=%v}",
+		r.keywords, r.separators, r.quotes, r.redactor)
+}
+
+// Option defines options for creating new keyword redactors.
+type Option func(*KeywordRedactor)
+
+/*
+WithKeywords sets the trigger keywords whose values are redacted. Default is
+the keywords returned by DefaultKeywords.
+*/
+func WithKeywords(keywords ...string) Option {
+	return func(r *KeywordRedactor) {
+		r.keywords = normalize(keywords)
+	}
+}
+
+/*
+WithSeparators sets the separators between keywords and values. Separators
+ending in a letter or digit, such as "is", must be whole words. Default is
+the separators returned by DefaultSeparators.
+*/
+func WithSeparators(separators ...string) Option {
+	return func(r *KeywordRedactor) {
+		r.separators = normalize(separators)
+	}
+}
+
+/*
+WithQuotes sets the characters quoting keywords and values. Default is
+"\"'`".
+*/
+func WithQuotes(quotes string) Option {
+	return func(r *KeywordRedactor) {
+		r.quotes = quotes
+	}
+}
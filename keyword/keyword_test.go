@@ -0,0 +1,313 @@
+package keyword
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/internal/redacttest"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+const replacement = "[redacted]"
+
+func TestRedact_keywordBoundaries(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"nothing to see here", "nothing to see here"},
+		{"password: hunter2", "password: [redacted]"},
+		{"Password:hunter2 user: bob", "Password:[redacted] user: bob"},
+		{"the api key is abc123.", "the api key is [redacted]."},
+		{"API_KEY=abc123 API-KEY => abc123", "API_KEY=[redacted] API-KEY => [redacted]"},
+		{"db_password=s3cret&user=bob", "db_password=[redacted]&user=bob"},
+		{"(password=hunter2)", "(password=[redacted])"},
+		{"passwords: a", "passwords: a"},
+		{"mypassword: a", "mypassword: a"},
+		{"password_hash: a", "password_hash: a"},
+		{"password hunter2", "password hunter2"},
+		{"password isn't set", "password isn't set"},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_valueExtent(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{`{"secret":"abc def","user":"bob"}`, `{"secret":"[redacted]","user":"bob"}`},
+		{`{"access_token": "abc", "token_type": "bearer"}`, `{"access_token": "[redacted]", "token_type": "bearer"}`},
+		{`{'secret': 'it''s'}`, `{'secret': '[redacted]''s'}`},
+		{`{"secret": 12345, "n": 1}`, `{"secret": [redacted], "n": 1}`},
+		{"secret := `abc`", "secret := `[redacted]`"},
+		{"password: \"unterminated\nnext", "password: \"[redacted]\nnext"},
+		{"password:\nhunter2", "password:\nhunter2"},
+		{"password: ", "password: "},
+		{"token=a token=b", "token=[redacted] token=[redacted]"},
+		{"pwd=v1.2.3", "pwd=[redacted]"},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_authSchemes(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"Authorization: Bearer eyJhbGciOi.abc", "Authorization: Bearer [redacted]"},
+		{"authorization: Basic dXNlcjpwYXNz\nnext", "authorization: Basic [redacted]\nnext"},
+		{"Authorization: xyz", "Authorization: [redacted]"},
+		{"Authorization: Bearer", "Authorization: [redacted]"},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_escapedJSON(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{`msg="{\"password\":\"hunter2\"}"`, `msg="{\"password\":\"[redacted]\"}"`},
+		{`"{\"password\": \"hunter2\", \"user\": \"bob\"}"`, `"{\"password\": \"[redacted]\", \"user\": \"bob\"}"`},
+		{`"{\"secret\": 12345, \"n\": 1}"`, `"{\"secret\": [redacted], \"n\": 1}"`},
+		{`"{\"token\":\"unterminated"`, `"{\"token\":\"[redacted]"`},
+		{`"{\"user\":\"bob\"}"`, `"{\"user\":\"bob\"}"`},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_escapedJSONValues(t *testing.T) {
+	var seen []string
+	recorder := redacttest.Func(func(s string) (string, error) {
+		seen = append(seen, s)
+		return `x"y\z`, nil
+	})
+
+	// the JSON object {"token":"a\"b\\c"} within a JSON string
+	actual, err := New(recorder).Redact(`{\"token\":\"a\\\"b\\\\c\"}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if expected := `{\"token\":\"x\\\"y\\\\z\"}`; actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+	if expected := []string{`a"b\c`}; fmt.Sprint(seen) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%q', but got '%q'", expected, seen)
+	}
+}
+
+func TestRedact_quotedValues(t *testing.T) {
+	var seen []string
+	recorder := redacttest.Func(func(s string) (string, error) {
+		seen = append(seen, s)
+		return `a"b\c`, nil
+	})
+
+	actual, err := New(recorder).Redact(`secret="x\"y\\z" token='q'`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if expected := `secret="a\"b\\c" token='a"b\\c'`; actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+	if expected := []string{`x"y\z`, "q"}; fmt.Sprint(seen) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%q', but got '%q'", expected, seen)
+	}
+}
+
+func TestWithKeywords(t *testing.T) {
+	redactor, err := NewFromOptions(simple.New(replacement), WithKeywords("PIN", "otp code"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"pin: 1234 otp_code=987 password=x", "pin: [redacted] otp_code=[redacted] password=x"},
+		{"OTP-Code: 42", "OTP-Code: [redacted]"},
+		{"spin: 1234", "spin: 1234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithSeparators(t *testing.T) {
+	redactor, err := NewFromOptions(simple.New(replacement), WithSeparators("->", "is set to"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"password -> a, token is set to b, secret=c", "password -> [redacted], token is set to [redacted], secret=c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithQuotes(t *testing.T) {
+	redactor, err := NewFromOptions(simple.New(replacement), WithQuotes(`"`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{`secret='a b' token="c d"`, `secret=[redacted] b' token="[redacted]"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_redactorNotCalledWithoutKeyword(t *testing.T) {
+	redactor := New(redacttest.Failing{})
+
+	if _, err := redactor.Redact("password hunter2, no separator"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := redactor.Redact("password: x"); !errors.Is(err, redacttest.ErrFailed) {
+		t.Errorf("Expected '%v', but got '%v'", redacttest.ErrFailed, err)
+	}
+}
+
+func TestDetect_byteOffsets(t *testing.T) {
+	input := `{"nom": "Émile", "secret": "ümlaut"}`
+	spans, err := New(simple.New(replacement)).(redact.Detector).Detect(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := strings.Index(input, "ümlaut")
+	expected := []redact.Span{{Start: start, End: start + len("ümlaut"), Replacement: replacement}}
+	if fmt.Sprint(spans) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%v', but got '%v'", expected, spans)
+	}
+}
+
+func TestNewFromOptions_errors(t *testing.T) {
+	var tests = []struct {
+		name     string
+		redactor redact.Redactor
+		opts     []Option
+		expected error
+	}{
+		{"nil redactor", nil, nil, errRedactorNil},
+		{"blank keywords", simple.New(replacement), []Option{WithKeywords(" ", "")}, errKeywordsEmpty},
+		{"no separators", simple.New(replacement), []Option{WithSeparators()}, errSeparatorsEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFromOptions(tt.redactor, tt.opts...); err != tt.expected {
+				t.Errorf("Expected '%v', but got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	redactor, err := NewFromOptions(simple.New("x"), WithKeywords("pin", "otp code"), WithSeparators("=", ":="))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{keywords=["otp code" "pin"]; separators=[":=" "="]; quotes="\"'` + "`" + `"; redactor={replacement="x"}}`
+	if actual := fmt.Sprint(redactor); actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
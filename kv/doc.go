@@ -0,0 +1,5 @@
+// Package kv provides a redactor for key/value formatted text, such as
+// logfmt lines. It tokenizes the input, taking quoted values and escapes
+// into account, and redacts the values of the keys to be redacted while
+// preserving the rest of the line.
+package kv
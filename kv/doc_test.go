@@ -0,0 +1,20 @@
+package kv
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func ExampleNew() {
+	redactor := New(simple.New("[redacted]"))
+
+	result, err := redactor.Redact(`level=info user=bob password="s3cr et" msg="login ok"`)
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+
+	fmt.Println(result)
+	// Output: level=info user=bob password="[redacted]" msg="login ok"
+}
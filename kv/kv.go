@@ -0,0 +1,388 @@
+package kv
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kristinjeanna/redact"
+)
+
+const (
+	defaultSeparators = "="
+	defaultQuotes     = `"`
+	defaultEscape     = '\\'
+)
+
+var (
+	errRedactorNil     = errors.New("kv.NewFromOptions: redactor must not be nil")
+	errSeparatorsEmpty = errors.New("kv.NewFromOptions: at least one separator is required")
+	errKeysEmpty       = errors.New("kv.NewFromOptions: at least one key or a key pattern is required")
+
+	errMsgFmtAmbiguousRune = "kv.NewFromOptions: %q is used for more than one purpose"
+	errMsgFmtRedactFailure = "kv.KVRedactor.Redact: error while redacting, %w"
+)
+
+// DefaultKeys returns the keys whose values are redacted by default.
+func DefaultKeys() []string {
+	return []string{
+		"password",
+		"passwd",
+		"pwd",
+		"secret",
+		"token",
+		"access_token",
+		"refresh_token",
+		"api_key",
+		"apikey",
+		"client_secret",
+		"private_key",
+		"authorization",
+		"cookie",
+	}
+}
+
+// KVRedactor is a redactor for key/value formatted text, such as the logfmt
+// line
+//
+//	level=info user=bob password="s3cr et"
+//
+// Keys run up to a separator, and values up to the next whitespace, unless
+// they are quoted. The values of keys to be redacted are replaced by the
+// output of a redactor, keeping their original quoting. A value that was
+// not quoted is quoted when the output would otherwise not be read back as
+// a single value.
+type KVRedactor struct {
+	redactor             redact.Redactor
+	keys                 map[string]bool
+	keyPattern           *regexp.Regexp
+	separators           string
+	spacesAfterSeparator bool
+	quotes               string
+	escape               rune
+}
+
+// New returns a new KVRedactor for logfmt text that replaces the values of
+// the default keys with the output of the redactor.
+func New(redactor redact.Redactor) redact.Redactor {
+	return newKV(redactor)
+}
+
+// NewFromOptions returns a new KVRedactor that replaces values with the
+// output of the redactor, with the provided options.
+func NewFromOptions(redactor redact.Redactor, opts ...Option) (redact.Redactor, error) {
+	if redactor == nil {
+		return nil, errRedactorNil
+	}
+
+	r := newKV(redactor)
+	for _, o := range opts {
+		o(&r)
+	}
+
+	if len(r.separators) == 0 {
+		return nil, errSeparatorsEmpty
+	}
+
+	if len(r.keys) == 0 && r.keyPattern == nil {
+		return nil, errKeysEmpty
+	}
+
+	special := r.separators + r.quotes
+	if r.escape != 0 {
+		special += string(r.escape)
+	}
+	for i, c := range special {
+		if strings.ContainsRune(special[i+utf8.RuneLen(c):], c) || unicode.IsSpace(c) {
+			return nil, fmt.Errorf(errMsgFmtAmbiguousRune, c)
+		}
+	}
+
+	return r, nil
+}
+
+// newKV returns a KVRedactor with the default settings.
+func newKV(redactor redact.Redactor) KVRedactor {
+	return KVRedactor{
+		redactor:   redactor,
+		keys:       keySet(DefaultKeys()),
+		separators: defaultSeparators,
+		quotes:     defaultQuotes,
+		escape:     defaultEscape,
+	}
+}
+
+// Redact redacts the values of the matching keys in s.
+func (r KVRedactor) Redact(s string) (string, error) {
+	spans, err := r.Detect(s)
+	if err != nil {
+		return "", err
+	}
+
+	return redact.ApplySpans(s, spans), nil
+}
+
+// Detect returns the spans of s holding the values of the matching keys,
+// including their quotes, along with their redacted and requoted
+// replacements.
+func (r KVRedactor) Detect(s string) ([]redact.Span, error) {
+	var spans []redact.Span
+
+	for i := 0; i < len(s); {
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if unicode.IsSpace(c) {
+			i += size
+			continue
+		}
+
+		keyStart := i
+		for i < len(s) {
+			c, size = utf8.DecodeRuneInString(s[i:])
+			if unicode.IsSpace(c) || r.isSeparator(c) {
+				break
+			}
+			i += size
+		}
+		key := s[keyStart:i]
+
+		if i == len(s) || !r.isSeparator(c) {
+			continue // bare word
+		}
+		i += size
+
+		if r.spacesAfterSeparator {
+			for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+				i++
+			}
+		}
+
+		v := r.scanValue(s, i)
+		i = v.end
+
+		if key == "" || !r.matches(key) {
+			continue
+		}
+
+		out, err := r.redactor.Redact(v.value)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFmtRedactFailure, err)
+		}
+		if out == v.value {
+			continue // keep the original quoting and escapes
+		}
+		spans = append(spans, redact.Span{Start: v.start, End: v.end, Replacement: r.format(out, v)})
+	}
+
+	return spans, nil
+}
+
+// String returns a text representation of the redactor.
+func (r KVRedactor) String() string {
+	keys := make([]string, 0, len(r.keys))
+	for k := range r.keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "{keys=%q; ", keys)
+	if r.keyPattern != nil {
+		fmt.Fprintf(&b, "keyPattern=%q; ", r.keyPattern)
+	}
+	fmt.Fprintf(&b, "separators=%q; quotes=%q; escape=%q; redactor=%v}", r.separators, r.quotes, r.escape, r.redactor)
+
+	return b.String()
+}
+
+// value is a value scanned from the input.
+type value struct {
+	start, end int    // byte offsets of the value, including its quotes
+	value      string // the unquoted and unescaped value
+	quote      rune   // the opening quote, or 0 if the value is not quoted
+	closed     bool   // whether the closing quote is present
+}
+
+// scanValue scans the value starting at byte offset i of s. Within quoted
+// values, the escape character makes an escaped quote or escape character
+// literal; other escape sequences are kept as they are.
+func (r KVRedactor) scanValue(s string, i int) value {
+	v := value{start: i}
+
+	c, size := utf8.DecodeRuneInString(s[i:])
+	if i == len(s) || !r.isQuote(c) {
+		for i < len(s) {
+			c, size = utf8.DecodeRuneInString(s[i:])
+			if unicode.IsSpace(c) {
+				break
+			}
+			i += size
+		}
+		v.end, v.value = i, s[v.start:i]
+		return v
+	}
+
+	v.quote = c
+	i += size
+
+	var b strings.Builder
+	for i < len(s) {
+		c, size = utf8.DecodeRuneInString(s[i:])
+		if c == v.quote {
+			v.closed = true
+			i += size
+			break
+		}
+
+		if c == r.escape && r.escape != 0 && i+size < len(s) {
+			next, nextSize := utf8.DecodeRuneInString(s[i+size:])
+			if next == v.quote || next == r.escape {
+				b.WriteRune(next)
+				i += size + nextSize
+				continue
+			}
+		}
+
+		b.WriteRune(c)
+		i += size
+	}
+
+	v.end, v.value = i, b.String()
+	return v
+}
+
+// format returns the redacted output for the scanned value v, quoted and
+// escaped like the original value.
+func (r KVRedactor) format(out string, v value) string {
+	quote := v.quote
+	if quote == 0 {
+		if len(r.quotes) == 0 || !r.needsQuotes(out) {
+			return out
+		}
+		quote, _ = utf8.DecodeRuneInString(r.quotes)
+		v.closed = true
+	}
+
+	var b strings.Builder
+	b.WriteRune(quote)
+	for i, c := range out {
+		if c == quote {
+			if r.escape != 0 {
+				b.WriteRune(r.escape)
+			}
+		} else if c == r.escape && r.escape != 0 {
+			next, _ := utf8.DecodeRuneInString(out[i+utf8.RuneLen(c):])
+			if next == quote || next == r.escape || i+utf8.RuneLen(c) == len(out) {
+				b.WriteRune(r.escape)
+			}
+		}
+		b.WriteRune(c)
+	}
+	if v.closed {
+		b.WriteRune(quote)
+	}
+
+	return b.String()
+}
+
+// needsQuotes reports whether an unquoted value would not be read back as
+// the same value.
+func (r KVRedactor) needsQuotes(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	first, _ := utf8.DecodeRuneInString(s)
+	return r.isQuote(first) || strings.IndexFunc(s, unicode.IsSpace) >= 0
+}
+
+// matches reports whether the values of the key are redacted.
+func (r KVRedactor) matches(key string) bool {
+	return r.keys[strings.ToLower(key)] || (r.keyPattern != nil && r.keyPattern.MatchString(key))
+}
+
+func (r KVRedactor) isSeparator(c rune) bool {
+	return strings.ContainsRune(r.separators, c)
+}
+
+func (r KVRedactor) isQuote(c rune) bool {
+	return strings.ContainsRune(r.quotes, c)
+}
+
+// keySet returns the set of keys in lower case.
+func keySet(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(k)] = true
+	}
+	return set
+}
+
+// Option defines options for creating new kv redactors.
+type Option func(*KVRedactor)
+
+/*
+WithKeys sets the keys whose values are redacted, replacing the default
+ones. Keys are matched ignoring case. Default is the keys returned by
+DefaultKeys.
+*/
+func WithKeys(keys ...string) Option {
+	return func(r *KVRedactor) {
+		r.keys = keySet(keys)
+	}
+}
+
+/*
+WithKeyPattern sets a regular expression matching additional keys whose
+values are redacted. Default is nil.
+*/
+func WithKeyPattern(pattern *regexp.Regexp) Option {
+	return func(r *KVRedactor) {
+		r.keyPattern = pattern
+	}
+}
+
+/*
+WithSeparators sets the characters separating keys from values, e.g. "=:"
+to accept both "key=value" and "key:value". Default is "=".
+*/
+func WithSeparators(separators string) Option {
+	return func(r *KVRedactor) {
+		r.separators = separators
+	}
+}
+
+/*
+WithSpacesAfterSeparator sets whether spaces and tabs between a separator and
+a value are skipped, as needed for "key: value" text. In logfmt, "key= value"
+denotes an empty value for "key" followed by the bare word "value". Default
+is false.
+*/
+func WithSpacesAfterSeparator(allow bool) Option {
+	return func(r *KVRedactor) {
+		r.spacesAfterSeparator = allow
+	}
+}
+
+/*
+WithQuotes sets the characters that may enclose a value, which may then
+contain whitespace. An empty string disables quoting. Default is `"`.
+*/
+func WithQuotes(quotes string) Option {
+	return func(r *KVRedactor) {
+		r.quotes = quotes
+	}
+}
+
+/*
+WithEscape sets the character that escapes a quote or itself within a quoted
+value. Zero disables escaping. Default is '\'.
+*/
+func WithEscape(escape rune) Option {
+	return func(r *KVRedactor) {
+		r.escape = escape
+	}
+}
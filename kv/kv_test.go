@@ -0,0 +1,208 @@
+package kv
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/internal/redacttest"
+	"github.com/kristinjeanna/redact/middle"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+const replacement = "[redacted]"
+
+func TestRedact_logfmt(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"level=info user=bob", "level=info user=bob"},
+		{"level=info password=s3cret user=bob", "level=info password=[redacted] user=bob"},
+		{`level=info user=bob password="s3cr et"`, `level=info user=bob password="[redacted]"`},
+		{`password="s3\"cr et" user=bob`, `password="[redacted]" user=bob`},
+		{`password="s3cr\\" user=bob`, `password="[redacted]" user=bob`},
+		{`password="unterminated user=bob`, `password="[redacted]`},
+		{"PASSWORD=s3cret", "PASSWORD=[redacted]"},
+		{"password= user=bob", "password=[redacted] user=bob"},
+		{"password", "password"},
+		{"msg=password token=abc", "msg=password token=[redacted]"},
+		{"=token token==x", "=token token=[redacted]"},
+		{"a=1\ntoken=abc\tb=2\n", "a=1\ntoken=[redacted]\tb=2\n"},
+		{"msg=héllo token=ünïcode", "msg=héllo token=[redacted]"},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_requoting(t *testing.T) {
+	var tests = []struct {
+		replacement string
+		input       string
+		expected    string
+	}{
+		{"[redacted value]", "token=abc", `token="[redacted value]"`},
+		{`"x"`, "token=abc", `token="\"x\""`},
+		{`a"b`, "token=abc", `token=a"b`},
+		{`a"b`, `token="abc"`, `token="a\"b"`},
+		{`a\b`, `token="abc"`, `token="a\b"`},
+		{`a\"`, `token="abc"`, `token="a\\\""`},
+		{`ab\`, `token="abc"`, `token="ab\\"`},
+		{"", `token="abc"`, `token=""`},
+		{"", "token=abc", "token="},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("replacement=%q;input=%q", tt.replacement, tt.input), func(t *testing.T) {
+			actual, err := New(simple.New(tt.replacement)).Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_unescapedValue(t *testing.T) {
+	var seen []string
+	recorder := redacttest.Func(func(s string) (string, error) {
+		seen = append(seen, s)
+		return s, nil
+	})
+
+	input := `token="a\"b\\c\nd" secret=plain`
+	actual, err := New(recorder).Redact(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{`a"b\c\nd`, "plain"}
+	if fmt.Sprint(seen) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%q', but got '%q'", expected, seen)
+	}
+	if actual != input {
+		t.Errorf("Expected '%s', but got '%s'", input, actual)
+	}
+}
+
+func TestRedact_options(t *testing.T) {
+	middleRedactor, err := middle.NewFromOptions(middle.WithReplacementText("***"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		opts     []Option
+		redactor redact.Redactor
+		input    string
+		expected string
+	}{
+		{[]Option{WithKeys("ssn")}, simple.New(replacement), "ssn=123-45-6789 password=x", "ssn=[redacted] password=x"},
+		{[]Option{WithKeyPattern(regexp.MustCompile(`(?i)_key$`))}, simple.New(replacement), "aws_key=x api_KEY=y key=z", "aws_key=[redacted] api_KEY=[redacted] key=z"},
+		{[]Option{WithSeparators(":="), WithSpacesAfterSeparator(true)}, simple.New(replacement), "user: bob password: s3cret token=abc", "user: bob password: [redacted] token=[redacted]"},
+		{[]Option{WithSeparators(":")}, simple.New(replacement), "password: s3cret", "password:[redacted] s3cret"},
+		{[]Option{WithQuotes(`"'`)}, simple.New(replacement), `password='s3 cret' token="a b"`, `password='[redacted]' token="[redacted]"`},
+		{[]Option{WithQuotes("")}, simple.New(replacement), `password="s3 cret"`, `password=[redacted] cret"`},
+		{[]Option{WithEscape(0)}, simple.New(replacement), `password="s3\" x=y`, `password="[redacted]" x=y`},
+		{[]Option{WithEscape('^')}, simple.New(replacement), `password="s3^"c\" x=y`, `password="[redacted]" x=y`},
+		{nil, middleRedactor, "token=0123456789", "token=012***789"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			redactor, err := NewFromOptions(tt.redactor, tt.opts...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDetect_quotedValue(t *testing.T) {
+	redactor := New(simple.New(replacement)).(redact.Detector)
+
+	spans, err := redactor.Detect(`user=bob password="s3cr et" token=x`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []redact.Span{
+		{Start: 18, End: 27, Replacement: `"[redacted]"`},
+		{Start: 34, End: 35, Replacement: replacement},
+	}
+	if fmt.Sprint(spans) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%v', but got '%v'", expected, spans)
+	}
+}
+
+func TestNewFromOptions_errors(t *testing.T) {
+	var tests = []struct {
+		redactor redact.Redactor
+		opts     []Option
+		expected string
+	}{
+		{nil, nil, errRedactorNil.Error()},
+		{simple.New(replacement), []Option{WithSeparators("")}, errSeparatorsEmpty.Error()},
+		{simple.New(replacement), []Option{WithKeys()}, errKeysEmpty.Error()},
+		{simple.New(replacement), []Option{WithSeparators(`="`)}, fmt.Sprintf(errMsgFmtAmbiguousRune, '"')},
+		{simple.New(replacement), []Option{WithEscape('"')}, fmt.Sprintf(errMsgFmtAmbiguousRune, '"')},
+		{simple.New(replacement), []Option{WithSeparators(" ")}, fmt.Sprintf(errMsgFmtAmbiguousRune, ' ')},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("expected=%q", tt.expected), func(t *testing.T) {
+			_, err := NewFromOptions(tt.redactor, tt.opts...)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Expected error '%s', but got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestRedact_error(t *testing.T) {
+	redactor := New(redacttest.Failing{})
+
+	// keys that are not redacted never invoke the redactor
+	if _, err := redactor.Redact("user=bob tokens=x"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := redactor.Redact("token=x"); !errors.Is(err, redacttest.ErrFailed) {
+		t.Errorf("Expected '%v', but got '%v'", redacttest.ErrFailed, err)
+	}
+}
+
+func TestString(t *testing.T) {
+	redactor, err := NewFromOptions(simple.New(replacement), WithKeys("b", "A"), WithKeyPattern(regexp.MustCompile("_key$")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{keys=["a" "b"]; keyPattern="_key$"; separators="="; quotes="\""; escape='\\'; redactor={replacement="[redacted]"}}`
+	if fmt.Sprint(redactor) != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, fmt.Sprint(redactor))
+	}
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+// Stats holds the accumulated statistics of a named redactor.
+type Stats struct {
+	Calls         uint64        `json:"calls"`          // number of redactions
+	Matches       uint64        `json:"matches"`        // number of matches redacted
+	Errors        uint64        `json:"errors"`         // number of failed redactions
+	InputBytes    uint64        `json:"input_bytes"`    // total length of the input strings
+	OutputBytes   uint64        `json:"output_bytes"`   // total length of the output strings
+	TotalDuration time.Duration `json:"total_duration"` // total time spent redacting
+	MaxDuration   time.Duration `json:"max_duration"`   // longest time spent on one redaction
+}
+
+// Counters is a Hook that accumulates statistics in memory for each
+// redactor name. It is safe for concurrent use. The zero value is ready to
+// use.
+type Counters struct {
+	mu    sync.Mutex
+	stats map[string]*Stats
+}
+
+// NewCounters returns a new, empty Counters.
+func NewCounters() *Counters {
+	return &Counters{stats: make(map[string]*Stats)}
+}
+
+// OnRedact records a redaction.
+func (c *Counters) OnRedact(name string, inputLen, outputLen, matches int, duration time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stats == nil {
+		c.stats = make(map[string]*Stats)
+	}
+
+	s, ok := c.stats[name]
+	if !ok {
+		s = &Stats{}
+		c.stats[name] = s
+	}
+
+	s.Calls++
+	s.Matches += uint64(matches)
+	if err != nil {
+		s.Errors++
+	}
+	s.InputBytes += uint64(inputLen)
+	s.OutputBytes += uint64(outputLen)
+	s.TotalDuration += duration
+	if duration > s.MaxDuration {
+		s.MaxDuration = duration
+	}
+}
+
+// Get returns the statistics recorded for the named redactor.
+func (c *Counters) Get(name string) Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s, ok := c.stats[name]; ok {
+		return *s
+	}
+	return Stats{}
+}
+
+// Snapshot returns a copy of the statistics recorded for every redactor,
+// keyed by name.
+func (c *Counters) Snapshot() map[string]Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := make(map[string]Stats, len(c.stats))
+	for name, s := range c.stats {
+		snapshot[name] = *s
+	}
+	return snapshot
+}
+
+// Reset discards all recorded statistics.
+func (c *Counters) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats = make(map[string]*Stats)
+}
+
+// PublishExpvar exports a snapshot of the statistics as the expvar variable
+// with the specified name, for example to be served by the /debug/vars
+// handler. Like expvar.Publish, it panics if the name is already in use.
+func (c *Counters) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return c.Snapshot()
+	}))
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCounters(t *testing.T) {
+	var counters Counters // the zero value is ready to use
+
+	counters.OnRedact("a", 10, 8, 2, 3*time.Millisecond, nil)
+	counters.OnRedact("a", 5, 5, 0, 1*time.Millisecond, errors.New("failed"))
+	counters.OnRedact("b", 1, 1, 0, 0, nil)
+
+	expected := Stats{
+		Calls:         2,
+		Matches:       2,
+		Errors:        1,
+		InputBytes:    15,
+		OutputBytes:   13,
+		TotalDuration: 4 * time.Millisecond,
+		MaxDuration:   3 * time.Millisecond,
+	}
+	if got := counters.Get("a"); expected != got {
+		t.Errorf("Expected '%+v', but got '%+v'", expected, got)
+	}
+
+	if got := len(counters.Snapshot()); got != 2 {
+		t.Errorf("Expected 2 entries, but got %d", got)
+	}
+
+	counters.Reset()
+	if got := counters.Get("a"); got != (Stats{}) {
+		t.Errorf("Expected empty stats, but got '%+v'", got)
+	}
+}
+
+func TestCounters_concurrent(t *testing.T) {
+	counters := NewCounters()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				counters.OnRedact("rule", 1, 1, 1, time.Microsecond, nil)
+				_ = counters.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := counters.Get("rule").Calls; got != 800 {
+		t.Errorf("Expected 800 calls, but got %d", got)
+	}
+}
+
+func TestCounters_PublishExpvar(t *testing.T) {
+	counters := NewCounters()
+	counters.PublishExpvar("redact_metrics_test")
+	counters.OnRedact("ssn", 20, 20, 1, time.Millisecond, nil)
+
+	v := expvar.Get("redact_metrics_test")
+	if v == nil {
+		t.Fatal("Expected the expvar variable to be published")
+	}
+
+	var got map[string]Stats
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["ssn"].Matches != 1 || got["ssn"].Calls != 1 {
+		t.Errorf("Unexpected stats '%+v'", got["ssn"])
+	}
+}
@@ -0,0 +1,5 @@
+// Package metrics provides instrumentation for redactors: the Hook
+// interface notified of every redaction, the InstrumentedRedactor wrapper,
+// and Counters, an in-memory Hook implementation that can be exported via
+// the expvar package.
+package metrics
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/chain"
+	"github.com/kristinjeanna/redact/substring"
+)
+
+func ExampleInstrument() {
+	counters := NewCounters()
+
+	// instrument each chain step under its own name
+	redactor := chain.New([]redact.Redactor{
+		Instrument("password", substring.New("password", "XXXXX"), counters),
+		Instrument("token", substring.New("token", "XXXXX"), counters),
+	})
+
+	for _, s := range []string{"password=abc", "password=xyz", "user=bob"} {
+		if _, err := redactor.Redact(s); err != nil {
+			log.Fatalf("an error occurred while redacting: %s", err)
+		}
+	}
+
+	fmt.Println(counters.Get("password").Matches, counters.Get("token").Matches)
+	// Output: 2 0
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kristinjeanna/redact"
+)
+
+// Hook is notified of every redaction performed by an instrumented
+// redactor, regex pair or chain step.
+type Hook interface {
+
+	// OnRedact is called after a redaction with the name of the redactor,
+	// the byte lengths of the input and output strings, the number of
+	// matches redacted, the time taken and the error, if any.
+	OnRedact(name string, inputLen, outputLen, matches int, duration time.Duration, err error)
+}
+
+// HookFunc is an adapter allowing an ordinary function to be used as a Hook.
+type HookFunc func(name string, inputLen, outputLen, matches int, duration time.Duration, err error)
+
+// OnRedact calls f.
+func (f HookFunc) OnRedact(name string, inputLen, outputLen, matches int, duration time.Duration, err error) {
+	f(name, inputLen, outputLen, matches, duration, err)
+}
+
+// InstrumentedRedactor is a redactor that notifies a hook of every
+// redaction performed by an underlying redactor.
+type InstrumentedRedactor struct {
+	name     string
+	redactor redact.Redactor
+	hook     Hook
+}
+
+// instrumentedDetector is an InstrumentedRedactor for an underlying
+// redactor implementing redact.Detector.
+type instrumentedDetector struct {
+	InstrumentedRedactor
+}
+
+// Instrument returns a redactor that notifies the hook of every redaction
+// performed by the specified redactor under the specified name.
+//
+// If the redactor implements redact.Detector, so does the returned
+// redactor, and calls to Detect report the number of detected spans as
+// matches. Otherwise, a call to Redact reports one match if it changes the
+// input string and none if it doesn't.
+func Instrument(name string, redactor redact.Redactor, hook Hook) redact.Redactor {
+	r := InstrumentedRedactor{name: name, redactor: redactor, hook: hook}
+	if _, ok := redactor.(redact.Detector); ok {
+		return instrumentedDetector{r}
+	}
+	return r
+}
+
+// Redact invokes the underlying redactor and notifies the hook.
+func (r InstrumentedRedactor) Redact(s string) (string, error) {
+	start := time.Now()
+	out, err := r.redactor.Redact(s)
+	elapsed := time.Since(start)
+
+	matches := 0
+	if err == nil && out != s {
+		matches = 1
+	}
+	r.hook.OnRedact(r.name, len(s), len(out), matches, elapsed, err)
+
+	return out, err
+}
+
+// String returns a text representation of the redactor.
+func (r InstrumentedRedactor) String() string {
+	return fmt.Sprintf("{name=%q; redactor=%v}", r.name, r.redactor)
+}
+
+// Detect invokes the underlying detector and notifies the hook.
+func (r instrumentedDetector) Detect(s string) ([]redact.Span, error) {
+	start := time.Now()
+	spans, err := r.redactor.(redact.Detector).Detect(s)
+	elapsed := time.Since(start)
+
+	outputLen := len(s)
+	for _, span := range spans {
+		outputLen += len(span.Replacement) - (span.End - span.Start)
+	}
+	r.hook.OnRedact(r.name, len(s), outputLen, len(spans), elapsed, err)
+
+	return spans, err
+}
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/chain"
+	"github.com/kristinjeanna/redact/simple"
+	"github.com/kristinjeanna/redact/substring"
+	"github.com/kristinjeanna/redact/url"
+)
+
+func TestInstrument(t *testing.T) {
+	counters := NewCounters()
+	r := Instrument("password", substring.New("password", "XXX"), counters)
+
+	for _, s := range []string{"my password", "nothing", "password password"} {
+		if _, err := r.Redact(s); err != nil {
+			t.Error(err)
+		}
+	}
+
+	expected := Stats{Calls: 3, Matches: 2, InputBytes: 11 + 7 + 17, OutputBytes: 6 + 7 + 7}
+	got := counters.Get("password")
+	got.TotalDuration, got.MaxDuration = 0, 0
+
+	if expected != got {
+		t.Errorf("Expected '%+v', but got '%+v'", expected, got)
+	}
+}
+
+func TestInstrument_error(t *testing.T) {
+	counters := NewCounters()
+	r := Instrument("url", url.New("XXX", nil), counters)
+
+	if _, err := r.Redact("not a url"); err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+
+	if got := counters.Get("url"); got.Calls != 1 || got.Errors != 1 || got.Matches != 0 {
+		t.Errorf("Unexpected stats '%+v'", got)
+	}
+}
+
+func TestInstrument_detector(t *testing.T) {
+	counters := NewCounters()
+	r := Instrument("secret", substring.New("secret", "[redacted]"), counters)
+
+	detector, ok := r.(redact.Detector)
+	if !ok {
+		t.Fatal("Expected the instrumented redactor to implement redact.Detector")
+	}
+
+	spans, err := detector.Detect("a secret and a secret")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(spans) != 2 {
+		t.Errorf("Expected 2 spans, but got %d", len(spans))
+	}
+
+	expected := Stats{Calls: 1, Matches: 2, InputBytes: 21, OutputBytes: 29}
+	got := counters.Get("secret")
+	got.TotalDuration, got.MaxDuration = 0, 0
+	if expected != got {
+		t.Errorf("Expected '%+v', but got '%+v'", expected, got)
+	}
+
+	if _, ok := Instrument("simple", simple.New("X"), counters).(redact.Detector); ok {
+		t.Error("Expected the instrumented redactor not to implement redact.Detector")
+	}
+}
+
+func TestInstrument_chainSteps(t *testing.T) {
+	counters := NewCounters()
+	steps := []redact.Redactor{
+		Instrument("password", substring.New("password", "XXX"), counters),
+		Instrument("token", substring.New("token", "XXX"), counters),
+	}
+
+	for _, mode := range []chain.Mode{chain.SequentialMode, chain.SpanMode} {
+		t.Run(fmt.Sprintf("mode=%q; ", mode), func(t *testing.T) {
+			counters.Reset()
+			r, err := chain.NewFromOptions(steps, chain.WithMode(mode))
+			if err != nil {
+				t.Error(err)
+			}
+
+			got, err := r.Redact("password password")
+			if err != nil {
+				t.Error(err)
+			}
+			if got != "XXX XXX" {
+				t.Errorf("Expected '%s', but got '%s'", "XXX XXX", got)
+			}
+
+			snapshot := counters.Snapshot()
+			if snapshot["password"].Calls != 1 || snapshot["password"].Matches == 0 {
+				t.Errorf("Unexpected stats for password step '%+v'", snapshot["password"])
+			}
+			if snapshot["token"].Calls != 1 || snapshot["token"].Matches != 0 {
+				t.Errorf("Unexpected stats for token step '%+v'", snapshot["token"])
+			}
+		})
+	}
+}
+
+func TestHookFunc(t *testing.T) {
+	var gotName string
+	var gotErr error
+	hook := HookFunc(func(name string, inputLen, outputLen, matches int, duration time.Duration, err error) {
+		gotName, gotErr = name, err
+	})
+
+	expectedErr := errors.New("failed")
+	hook.OnRedact("test", 1, 1, 0, time.Millisecond, expectedErr)
+	if gotName != "test" || gotErr != expectedErr {
+		t.Errorf("Unexpected hook arguments name=%q; err=%v", gotName, gotErr)
+	}
+}
+
+func TestString(t *testing.T) {
+	r := Instrument("test", simple.New("X"), NewCounters())
+
+	expected := `{name="test"; redactor={replacement="X"}}`
+	got := fmt.Sprint(r)
+
+	if expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
@@ -0,0 +1,5 @@
+// Package pem provides a redactor for PEM encoded blocks, such as private
+// keys and certificates, found anywhere in arbitrary text. Private keys are
+// always replaced entirely, while certificates can be kept, summarized or
+// redacted. The text surrounding the blocks is left untouched.
+package pem
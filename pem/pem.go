@@ -0,0 +1,297 @@
+package pem
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	pkgpem "encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+// CertificateMode determines how a PEMRedactor handles certificates.
+type CertificateMode int8
+
+const (
+	// RedactCertificates redacts certificates like any other block.
+	RedactCertificates CertificateMode = iota
+
+	// KeepCertificates leaves certificates intact.
+	KeepCertificates
+
+	// SummarizeCertificates replaces each certificate with a summary of its
+	// subject and SHA-256 fingerprint.
+	SummarizeCertificates
+)
+
+// String returns a text representation of the certificate mode.
+func (m CertificateMode) String() string {
+	switch m {
+	case KeepCertificates:
+		return "KeepCertificates"
+	case SummarizeCertificates:
+		return "SummarizeCertificates"
+	case RedactCertificates:
+		fallthrough
+	default:
+		return "RedactCertificates"
+	}
+}
+
+const (
+	beginMarker = "-----BEGIN "
+	endMarker   = "-----END "
+	markerEnd   = "-----"
+
+	certificateLabel = "CERTIFICATE"
+)
+
+const (
+	errMsgFmtCertificateMode = "pem.NewFromOptions: unknown certificate mode %d"
+	errMsgFmtRedactFailure   = "pem.PEMRedactor.Redact: error while redacting, %w"
+)
+
+// PEMRedactor is a redactor that finds PEM encoded blocks in text and
+// replaces them. A block starts with a "-----BEGIN <label>-----" line and
+// ends with the matching "-----END <label>-----" line. A block missing its
+// end line, e.g. in truncated output, extends over the following lines of
+// base64 data. Blocks are found even when their line breaks are escaped,
+// as in JSON strings, in which case a block missing its end line extends up
+// to the closing quote.
+//
+// Certificates are handled according to the certificate mode. All other
+// blocks, including private keys, are replaced by the output of the
+// redactor for the entire block or, by default, by "[redacted <label>]".
+type PEMRedactor struct {
+	redactor        redact.Redactor
+	certificateMode CertificateMode
+}
+
+// New returns a new PEMRedactor that redacts all blocks, including
+// certificates.
+func New() redact.Redactor {
+	return PEMRedactor{}
+}
+
+// NewFromOptions returns a new PEMRedactor with the provided options.
+func NewFromOptions(opts ...Option) (redact.Redactor, error) {
+	var r PEMRedactor
+	for _, o := range opts {
+		o(&r)
+	}
+
+	if r.certificateMode < RedactCertificates || r.certificateMode > SummarizeCertificates {
+		return nil, fmt.Errorf(errMsgFmtCertificateMode, r.certificateMode)
+	}
+
+	return r, nil
+}
+
+// Redact replaces the PEM blocks in s.
+func (r PEMRedactor) Redact(s string) (string, error) {
+	spans, err := r.Detect(s)
+	if err != nil {
+		return "", err
+	}
+
+	return redact.ApplySpans(s, spans), nil
+}
+
+// Detect returns the spans of the PEM blocks in s that are replaced.
+func (r PEMRedactor) Detect(s string) ([]redact.Span, error) {
+	var spans []redact.Span
+
+	for pos := 0; ; {
+		b, ok := findBlock(s, pos)
+		if !ok {
+			return spans, nil
+		}
+		pos = b.end
+
+		if b.label == certificateLabel && r.certificateMode == KeepCertificates {
+			continue
+		}
+
+		var repl string
+		if b.label == certificateLabel && r.certificateMode == SummarizeCertificates {
+			repl = summarize(s[b.start:b.end])
+		} else if r.redactor == nil {
+			repl = "[redacted " + b.label + "]"
+		} else {
+			var err error
+			if repl, err = r.redactor.Redact(s[b.start:b.end]); err != nil {
+				return nil, fmt.Errorf(errMsgFmtRedactFailure, err)
+			}
+		}
+
+		spans = append(spans, redact.Span{Start: b.start, End: b.end, Replacement: repl})
+	}
+}
+
+// String returns a text representation of the redactor.
+func (r PEMRedactor) String() string {
+	return fmt.Sprintf("{certificateMode=%q; redactor=%v}", r.certificateMode, r.redactor)
+}
+
+// block is the location and label of a PEM block.
+type block struct {
+	start, end int
+	label      string
+}
+
+// findBlock returns the first PEM block in s starting at or after pos.
+func findBlock(s string, pos int) (block, bool) {
+	for {
+		idx := strings.Index(s[pos:], beginMarker)
+		if idx < 0 {
+			return block{}, false
+		}
+		start := pos + idx
+		pos = start + len(beginMarker)
+
+		label, ok := parseLabel(s[pos:])
+		if !ok {
+			continue
+		}
+		bodyStart := pos + len(label) + len(markerEnd)
+
+		end := endMarker + label + markerEnd
+		if idx := strings.Index(s[bodyStart:], end); idx >= 0 {
+			return block{start: start, end: bodyStart + idx + len(end), label: label}, true
+		}
+
+		return block{start: start, end: bodyStart + dataLength(s[bodyStart:]), label: label}, true
+	}
+}
+
+// parseLabel returns the label at the start of s, which must be followed
+// by "-----".
+func parseLabel(s string) (string, bool) {
+	i := 0
+	for i < len(s) && (s[i] >= 'A' && s[i] <= 'Z' || s[i] >= '0' && s[i] <= '9' || s[i] == ' ') {
+		i++
+	}
+
+	if i == 0 || !strings.HasPrefix(s[i:], markerEnd) {
+		return "", false
+	}
+	return s[:i], true
+}
+
+// dataLength returns the length of the lines of base64 data and headers at
+// the start of s, which follows a begin line lacking its end line. Escaped
+// line breaks, as in JSON strings, also separate lines. Since the string
+// then ends on the last line, at a quote, that line's leading base64 data is
+// included as well.
+func dataLength(s string) int {
+	n, end := 0, 0
+	escaped := false
+	for n < len(s) {
+		line, brk, esc := nextLine(s[n:])
+		escaped = escaped || esc
+
+		content := strings.TrimSpace(line)
+		last := escaped && strings.ContainsAny(content, "\"'")
+		if last || content != "" && !isBase64(content) && !strings.Contains(content, ": ") {
+			if escaped {
+				lead := len(line) - len(strings.TrimLeft(line, " \t"))
+				if data := base64Prefix(line[lead:]); data > 0 {
+					end = n + lead + data
+				}
+			}
+			break
+		}
+
+		// keep the line break ending the data
+		if content != "" {
+			end = n + len(strings.TrimRight(line, " \t\r"))
+		}
+		n += len(line) + brk
+	}
+
+	return end
+}
+
+// nextLine returns the first line of s, the length of the line break ending
+// it and whether the line break is escaped, as `\n` or `\r\n`.
+func nextLine(s string) (string, int, bool) {
+	lf := strings.IndexByte(s, '\n')
+	esc := strings.Index(s, `\n`)
+	switch {
+	case esc >= 0 && (lf < 0 || esc < lf):
+		if strings.HasSuffix(s[:esc], `\r`) {
+			return s[:esc-2], 4, true
+		}
+		return s[:esc], 2, true
+	case lf >= 0:
+		return s[:lf], 1, false
+	default:
+		return s, 0, false
+	}
+}
+
+// base64Prefix returns the length of the base64 characters at the start of
+// s.
+func base64Prefix(s string) int {
+	i := 0
+	for i < len(s) && isBase64(s[i:i+1]) {
+		i++
+	}
+	return i
+}
+
+// isBase64 reports whether s consists of base64 characters only.
+func isBase64(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '+' || c == '/' || c == '=') {
+			return false
+		}
+	}
+	return true
+}
+
+// summarize returns a summary of the subject and SHA-256 fingerprint of the
+// certificate in the PEM block.
+func summarize(text string) string {
+	text = strings.ReplaceAll(text, `\n`, "\n") // escaped line breaks
+	p, _ := pkgpem.Decode([]byte(text))
+	if p == nil {
+		return "[certificate]"
+	}
+
+	sum := sha256.Sum256(p.Bytes)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	cert, err := x509.ParseCertificate(p.Bytes)
+	if err != nil {
+		return fmt.Sprintf("[certificate sha256=%s]", fingerprint)
+	}
+	return fmt.Sprintf("[certificate subject=%q sha256=%s]", cert.Subject.String(), fingerprint)
+}
+
+// Option defines options for creating new PEM redactors.
+type Option func(*PEMRedactor)
+
+/*
+WithRedactor sets the redactor whose output for an entire block replaces the
+block. Default is nil, which replaces blocks with "[redacted <label>]", e.g.
+"[redacted RSA PRIVATE KEY]".
+*/
+func WithRedactor(redactor redact.Redactor) Option {
+	return func(r *PEMRedactor) {
+		r.redactor = redactor
+	}
+}
+
+/*
+WithCertificateMode sets how certificates are handled. Default is
+"RedactCertificates".
+*/
+func WithCertificateMode(mode CertificateMode) Option {
+	return func(r *PEMRedactor) {
+		r.certificateMode = mode
+	}
+}
@@ -0,0 +1,81 @@
+package phone
+
+// lengths is the minimum and maximum number of digits of the national
+// significant number in a country.
+type lengths struct {
+	min, max int
+}
+
+// countryCodes maps the calling codes of the countries recognized in
+// international numbers to the lengths of their national numbers. Calling
+// codes are prefix-free, so at most one code matches a number.
+var countryCodes = map[string]lengths{
+	"1":   {10, 10}, // North American Numbering Plan
+	"7":   {10, 10}, // Russia, Kazakhstan
+	"20":  {8, 10},  // Egypt
+	"27":  {9, 9},   // South Africa
+	"30":  {10, 10}, // Greece
+	"31":  {9, 9},   // Netherlands
+	"32":  {8, 9},   // Belgium
+	"33":  {9, 9},   // France
+	"34":  {9, 9},   // Spain
+	"36":  {8, 9},   // Hungary
+	"39":  {6, 11},  // Italy
+	"40":  {9, 9},   // Romania
+	"41":  {9, 9},   // Switzerland
+	"43":  {4, 13},  // Austria
+	"44":  {7, 10},  // United Kingdom
+	"45":  {8, 8},   // Denmark
+	"46":  {7, 13},  // Sweden
+	"47":  {8, 8},   // Norway
+	"48":  {9, 9},   // Poland
+	"49":  {6, 13},  // Germany
+	"51":  {8, 9},   // Peru
+	"52":  {10, 10}, // Mexico
+	"54":  {10, 11}, // Argentina
+	"55":  {10, 11}, // Brazil
+	"56":  {9, 9},   // Chile
+	"57":  {8, 10},  // Colombia
+	"58":  {10, 10}, // Venezuela
+	"60":  {7, 10},  // Malaysia
+	"61":  {9, 9},   // Australia
+	"62":  {7, 12},  // Indonesia
+	"63":  {8, 10},  // Philippines
+	"64":  {8, 10},  // New Zealand
+	"65":  {8, 8},   // Singapore
+	"66":  {8, 9},   // Thailand
+	"81":  {9, 10},  // Japan
+	"82":  {8, 10},  // South Korea
+	"84":  {9, 10},  // Vietnam
+	"86":  {10, 11}, // China
+	"90":  {10, 10}, // Turkey
+	"91":  {10, 10}, // India
+	"92":  {9, 10},  // Pakistan
+	"94":  {9, 9},   // Sri Lanka
+	"98":  {10, 10}, // Iran
+	"212": {9, 9},   // Morocco
+	"234": {8, 10},  // Nigeria
+	"254": {9, 9},   // Kenya
+	"351": {9, 9},   // Portugal
+	"352": {4, 11},  // Luxembourg
+	"353": {7, 9},   // Ireland
+	"354": {7, 9},   // Iceland
+	"358": {5, 12},  // Finland
+	"380": {9, 9},   // Ukraine
+	"420": {9, 9},   // Czech Republic
+	"852": {8, 8},   // Hong Kong
+	"886": {8, 9},   // Taiwan
+	"966": {9, 9},   // Saudi Arabia
+	"971": {8, 9},   // United Arab Emirates
+	"972": {8, 9},   // Israel
+}
+
+// countryCode returns the calling code at the start of digits.
+func countryCode(digits string) (string, lengths, bool) {
+	for n := 1; n <= 3 && n <= len(digits); n++ {
+		if l, ok := countryCodes[digits[:n]]; ok {
+			return digits[:n], l, true
+		}
+	}
+	return "", lengths{}, false
+}
@@ -0,0 +1,5 @@
+// Package phone provides a redactor for telephone numbers found in text, in
+// international and North American formats. Candidate numbers are validated
+// against digit counts and a table of country calling codes, which yields
+// far fewer false positives than a plain digit regex.
+package phone
@@ -0,0 +1,23 @@
+package phone
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func ExampleNewFromOptions() {
+	redactor, err := NewFromOptions(simple.New("***"), WithKeepLast(4))
+	if err != nil {
+		log.Fatalf("an error occurred while creating redactor: %s", err)
+	}
+
+	result, err := redactor.Redact("Call me at (555) 234-5678 or +44 20 7946 0958, order 2023-10-19.")
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+
+	fmt.Println(result)
+	// Output: Call me at ***-5678 or *** 0958, order 2023-10-19.
+}
@@ -0,0 +1,297 @@
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+const (
+	defaultMinDigits = 8
+	defaultMaxDigits = 15 // the maximum length of an E.164 number
+)
+
+var (
+	errRedactorNil = errors.New("phone.NewFromOptions: redactor must not be nil")
+
+	errMsgFmtDigitRange    = "phone.NewFromOptions: invalid digit range %d-%d"
+	errMsgFmtRedactFailure = "phone.PhoneRedactor.Redact: error while redacting, %w"
+)
+
+var (
+	// candidateRegex matches candidate numbers: international numbers
+	// starting with "+" and North American numbers such as (555) 234-5678,
+	// optionally preceded by the country code 1.
+	candidateRegex = regexp.MustCompile(
+		`(?P<intl>\+ ?\d(?:[ .-]?\(?\d+\)?)*)` +
+			`|(?P<nanp>(?:1[ .-]?)?(?:\(\d{3}\) ?|\d{3}[ .-]?)\d{3}[ .-]?\d{4})`)
+
+	// extensionRegex matches an extension following a number.
+	extensionRegex = regexp.MustCompile(`^(?i:\s*(?:ext\.?|x|#)\s*\d{1,6})`)
+
+	// digitsRegex matches the runs of digits in a number.
+	digitsRegex = regexp.MustCompile(`\d+`)
+
+	intlIndex = candidateRegex.SubexpIndex("intl")
+)
+
+// PhoneRedactor is a redactor that replaces telephone numbers with the output
+// of another redactor. International numbers must start with "+" and a known
+// country calling code, followed by a national number of a valid length for
+// the country. North American numbers must have a valid area code and
+// exchange. Extensions, such as "ext. 12", are recognized but kept.
+type PhoneRedactor struct {
+	redactor  redact.Redactor
+	keepLast  uint
+	e164      bool
+	minDigits uint
+	maxDigits uint
+}
+
+// New returns a new PhoneRedactor that replaces numbers with the output of
+// the specified redactor.
+func New(redactor redact.Redactor) redact.Redactor {
+	return PhoneRedactor{
+		redactor:  redactor,
+		minDigits: defaultMinDigits,
+		maxDigits: defaultMaxDigits,
+	}
+}
+
+// NewFromOptions returns a new PhoneRedactor with the provided options.
+func NewFromOptions(redactor redact.Redactor, opts ...Option) (redact.Redactor, error) {
+	if redactor == nil {
+		return nil, errRedactorNil
+	}
+
+	r := New(redactor).(PhoneRedactor)
+	for _, o := range opts {
+		o(&r)
+	}
+
+	if r.minDigits == 0 || r.minDigits > r.maxDigits || r.maxDigits > defaultMaxDigits {
+		return nil, fmt.Errorf(errMsgFmtDigitRange, r.minDigits, r.maxDigits)
+	}
+
+	return r, nil
+}
+
+// Redact replaces the telephone numbers in s.
+func (r PhoneRedactor) Redact(s string) (string, error) {
+	spans, err := r.Detect(s)
+	if err != nil {
+		return "", err
+	}
+
+	return redact.ApplySpans(s, spans), nil
+}
+
+// Detect returns the spans of the telephone numbers in s.
+func (r PhoneRedactor) Detect(s string) ([]redact.Span, error) {
+	var spans []redact.Span
+
+	for pos := 0; pos < len(s); {
+		m := candidateRegex.FindStringSubmatchIndex(s[pos:])
+		if m == nil {
+			break
+		}
+		start, end := pos+m[0], pos+m[1]
+		pos = end
+
+		if !precededByBoundary(s, start) {
+			continue
+		}
+
+		var e164 string
+		var ok bool
+		if m[2*intlIndex] >= 0 {
+			end, e164, ok = r.international(s, start, end)
+		} else {
+			e164, ok = r.northAmerican(s[start:end])
+		}
+		if !ok || !followedByBoundary(s, end) {
+			continue
+		}
+		pos = end
+
+		span, err := r.span(s, start, end, e164)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFmtRedactFailure, err)
+		}
+		if span.Start < span.End {
+			spans = append(spans, span)
+		}
+	}
+
+	return spans, nil
+}
+
+// international validates the international number candidate at
+// s[start:end] and returns the end of the longest valid number, which may
+// omit trailing digits of the candidate, and its E.164 form.
+func (r PhoneRedactor) international(s string, start, end int) (int, string, bool) {
+	groups := digitsRegex.FindAllStringIndex(s[start:end], -1)
+
+	var digits string
+	for i := 0; i < len(groups); i++ {
+		g := groups[i]
+		if i == 1 && s[start+g[0]:start+g[1]] == "0" && strings.HasSuffix(s[:start+g[0]], "(") {
+			// trunk prefix of the national format, as in +44 (0)20
+			groups = append(groups[:i], groups[i+1:]...)
+			i--
+			continue
+		}
+		digits += s[start+g[0] : start+g[1]]
+	}
+	code, l, ok := countryCode(digits)
+	if !ok {
+		return 0, "", false
+	}
+
+	for i := len(groups) - 1; i >= 0; i-- {
+		national := len(digits) - len(code)
+		if national >= l.min && national <= l.max && r.validLength(len(digits)) &&
+			(code != "1" || validNANP(digits[1:])) {
+			return start + groups[i][1], "+" + digits, true
+		}
+		digits = digits[:len(digits)-(groups[i][1]-groups[i][0])]
+	}
+
+	return 0, "", false
+}
+
+// northAmerican validates the North American number candidate and returns
+// its E.164 form.
+func (r PhoneRedactor) northAmerican(candidate string) (string, bool) {
+	digits := strings.Join(digitsRegex.FindAllString(candidate, -1), "")
+	if len(digits) == 11 {
+		digits = digits[1:]
+	}
+
+	if !validNANP(digits) || !r.validLength(len(digits)+1) {
+		return "", false
+	}
+	return "+1" + digits, true
+}
+
+// validNANP reports whether the 10 digits form a valid North American
+// number, whose area code and exchange do not start with 0 or 1.
+func validNANP(digits string) bool {
+	return len(digits) == 10 && digits[0] >= '2' && digits[3] >= '2'
+}
+
+// validLength reports whether a number with n digits, including its country
+// code, is within the digit range.
+func (r PhoneRedactor) validLength(n int) bool {
+	return uint(n) >= r.minDigits && uint(n) <= r.maxDigits
+}
+
+// span returns the span replacing the number at s[start:end], whose E.164
+// form is e164.
+func (r PhoneRedactor) span(s string, start, end int, e164 string) (redact.Span, error) {
+	if r.keepLast > 0 {
+		kept := uint(0)
+		for end > start && kept < r.keepLast {
+			end--
+			if s[end] >= '0' && s[end] <= '9' {
+				kept++
+			}
+		}
+		// keep the separators preceding the kept digits
+		for end > start && (s[end-1] < '0' || s[end-1] > '9') {
+			end--
+		}
+	}
+
+	input := s[start:end]
+	if r.e164 {
+		input = e164
+	}
+
+	repl, err := r.redactor.Redact(input)
+	if err != nil {
+		return redact.Span{}, err
+	}
+	return redact.Span{Start: start, End: end, Replacement: repl}, nil
+}
+
+// precededByBoundary reports whether the number at s[start:] is not part of
+// a longer word or number.
+func precededByBoundary(s string, start int) bool {
+	if start == 0 {
+		return true
+	}
+
+	c := s[start-1]
+	if isAlnum(c) || c == '+' || c == '_' {
+		return false
+	}
+	return !(strings.IndexByte(".-/", c) >= 0 && start > 1 && isDigit(s[start-2]))
+}
+
+// followedByBoundary reports whether the number at s[:end] is not part of a
+// longer word or number, or is followed by an extension.
+func followedByBoundary(s string, end int) bool {
+	if end == len(s) || extensionRegex.MatchString(s[end:]) {
+		return true
+	}
+
+	c := s[end]
+	if isAlnum(c) || c == '_' {
+		return false
+	}
+	return !(strings.IndexByte(".-/", c) >= 0 && end+1 < len(s) && isDigit(s[end+1]))
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+// String returns a text representation of the redactor.
+func (r PhoneRedactor) String() string {
+	return fmt.Sprintf("{redactor=%v; keepLast=%d; e164=%t; minDigits=%d; maxDigits=%d}",
+		r.redactor, r.keepLast, r.e164, r.minDigits, r.maxDigits)
+}
+
+// Option defines options for creating new phone redactors.
+type Option func(*PhoneRedactor)
+
+/*
+WithKeepLast sets the number of trailing digits of each number that are kept
+visible, along with the separators between them. Only the text preceding
+them is redacted. Default is 0.
+*/
+func WithKeepLast(n uint) Option {
+	return func(r *PhoneRedactor) {
+		r.keepLast = n
+	}
+}
+
+/*
+WithE164 sets whether the redactor is passed the normalized E.164 form of each
+number, such as "+15552345678", instead of its text, e.g. for hashing or
+tokenizing numbers consistently across formats. Default is false.
+*/
+func WithE164(enabled bool) Option {
+	return func(r *PhoneRedactor) {
+		r.e164 = enabled
+	}
+}
+
+/*
+WithDigitRange sets the minimum and maximum number of digits of a number,
+including its country code. The maximum must not exceed 15. Default is 8 to 15.
+*/
+func WithDigitRange(min, max uint) Option {
+	return func(r *PhoneRedactor) {
+		r.minDigits = min
+		r.maxDigits = max
+	}
+}
@@ -0,0 +1,310 @@
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/internal/redacttest"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+const replacement = "[phone]"
+
+func TestRedact_northAmerican(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"no numbers here", "no numbers here"},
+		{"call (555) 234-5678 today", "call [phone] today"},
+		{"555-234-5678", "[phone]"},
+		{"555.234.5678", "[phone]"},
+		{"555 234 5678", "[phone]"},
+		{"5552345678", "[phone]"},
+		{"1-800-555-0199", "[phone]"},
+		{"+1 (555) 234-5678", "[phone]"},
+		{"+1-555-234-5678, +1.555.234.5679", "[phone], [phone]"},
+		{"555-234-5678 555-987-6543", "[phone] [phone]"},
+
+		// invalid area codes and exchanges
+		{"555-123-4567", "555-123-4567"},
+		{"155-234-5678", "155-234-5678"},
+		{"055-234-5678", "055-234-5678"},
+		{"+1 555 123 4567", "+1 555 123 4567"},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_international(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"+44 20 7946 0958", "[phone]"},
+		{"+44 (0)20 7946 0958", "[phone]"},
+		{"+49 30 901820", "[phone]"},
+		{"+33 1 23 45 67 89.", "[phone]."},
+		{"+44 20 7946 0958 12345", "[phone] 12345"},
+		{"tel:+15552345678", "tel:[phone]"},
+		{"+999 1234 5678", "+999 1234 5678"},
+		{"+44 12", "+44 12"},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_extensions(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"555-234-5678 ext. 12", "[phone] ext. 12"},
+		{"555-234-5678x12", "[phone]x12"},
+		{"555-234-5678 #7", "[phone] #7"},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_otherNumbers(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"1700000000", "1700000000"},
+		{"2023-10-19", "2023-10-19"},
+		{"192.168.100.200", "192.168.100.200"},
+		{"order 5552345678901", "order 5552345678901"},
+		{"12-555-234-5678", "12-555-234-5678"},
+		{"555-234-5678-9", "555-234-5678-9"},
+		{"abc5552345678", "abc5552345678"},
+		{"5552345678abc", "5552345678abc"},
+	}
+
+	redactor := New(simple.New(replacement))
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithKeepLast(t *testing.T) {
+	var tests = []struct {
+		keep     uint
+		input    string
+		expected string
+	}{
+		{4, "call (555) 234-5678", "call [phone]-5678"},
+		{2, "+44 20 7946 0958", "[phone]58"},
+		{5, "555-234-5678", "[phone]4-5678"},
+		{10, "555-234-5678", "555-234-5678"},
+		{4, "555-234-5678 ext. 12", "[phone]-5678 ext. 12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("keep=%d;input=%q", tt.keep, tt.input), func(t *testing.T) {
+			redactor, err := NewFromOptions(simple.New(replacement), WithKeepLast(tt.keep))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithKeepLast_redactorInput(t *testing.T) {
+	var seen []string
+	recorder := redacttest.Func(func(s string) (string, error) {
+		seen = append(seen, s)
+		return replacement, nil
+	})
+
+	redactor, err := NewFromOptions(recorder, WithKeepLast(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := redactor.Redact("(555) 234-5678"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// the separator before the kept digits is kept as well
+	if expected := []string{"(555) 234"}; fmt.Sprint(seen) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%q', but got '%q'", expected, seen)
+	}
+}
+
+func TestWithDigitRange(t *testing.T) {
+	var tests = []struct {
+		min, max uint
+		expected string
+	}{
+		{12, 15, "[phone] and 555-234-5678"},
+		{8, 11, "+44 20 7946 0958 and [phone]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("range=%d-%d", tt.min, tt.max), func(t *testing.T) {
+			redactor, err := NewFromOptions(simple.New(replacement), WithDigitRange(tt.min, tt.max))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			actual, err := redactor.Redact("+44 20 7946 0958 and 555-234-5678")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_e164(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"(555) 234-5678", "<+15552345678>"},
+		{"1.555.234.5678", "<+15552345678>"},
+		{"+1 555 234 5678 ext. 9", "<+15552345678> ext. 9"},
+		{"+44 (0)20 7946 0958", "<+442079460958>"},
+		{"+49-30-901820", "<+4930901820>"},
+	}
+
+	brackets := redacttest.Func(func(s string) (string, error) {
+		return "<" + s + ">", nil
+	})
+	redactor, err := NewFromOptions(brackets, WithE164(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_redactorError(t *testing.T) {
+	redactor := New(redacttest.Failing{})
+
+	if _, err := redactor.Redact("order 5552345678901"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := redactor.Redact("555-234-5678"); !errors.Is(err, redacttest.ErrFailed) {
+		t.Errorf("Expected '%v', but got '%v'", redacttest.ErrFailed, err)
+	}
+}
+
+func TestDetect_trunkPrefix(t *testing.T) {
+	spans, err := New(simple.New(replacement)).(redact.Detector).Detect("call +44 (0)20 7946 0958 now")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []redact.Span{{Start: 5, End: 24, Replacement: replacement}}
+	if fmt.Sprint(spans) != fmt.Sprint(expected) {
+		t.Errorf("Expected '%v', but got '%v'", expected, spans)
+	}
+}
+
+func TestNewFromOptions_errors(t *testing.T) {
+	var tests = []struct {
+		name     string
+		redactor redact.Redactor
+		opts     []Option
+		expected string
+	}{
+		{"nil redactor", nil, nil, errRedactorNil.Error()},
+		{"zero minimum", simple.New(replacement), []Option{WithDigitRange(0, 15)}, fmt.Sprintf(errMsgFmtDigitRange, 0, 15)},
+		{"minimum above maximum", simple.New(replacement), []Option{WithDigitRange(12, 10)}, fmt.Sprintf(errMsgFmtDigitRange, 12, 10)},
+		{"maximum above 15", simple.New(replacement), []Option{WithDigitRange(8, 16)}, fmt.Sprintf(errMsgFmtDigitRange, 8, 16)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFromOptions(tt.redactor, tt.opts...)
+			if err == nil {
+				t.Fatal("Expected error, but got nil")
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	redactor, err := NewFromOptions(simple.New("x"), WithKeepLast(4), WithE164(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{redactor={replacement="x"}; keepLast=4; e164=true; minDigits=8; maxDigits=15}`
+	if actual := fmt.Sprint(redactor); actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
@@ -1,8 +1,49 @@
 package redact
 
+import "strings"
+
 // Redactor is the common interface implemented by all redactors.
 type Redactor interface {
 
 	// Redact redacts the input string and returns the result.
 	Redact(s string) (string, error)
 }
+
+// Span is a region of an input string, identified by its byte offsets, and
+// the text that replaces it when the input is redacted.
+type Span struct {
+	Start       int    // byte offset of the start of the region
+	End         int    // byte offset just past the end of the region
+	Replacement string // text that replaces the region
+}
+
+// Detector is implemented by redactors that can report the regions of an
+// input string they redact without modifying it.
+type Detector interface {
+	Redactor
+
+	// Detect returns the non-overlapping spans of the input string that
+	// the redactor replaces, ordered by position.
+	Detect(s string) ([]Span, error)
+}
+
+// ApplySpans replaces each span in s with its replacement text. The spans
+// must not overlap and must be ordered by position.
+func ApplySpans(s string, spans []Span) string {
+	if len(spans) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	last := 0
+
+	for _, span := range spans {
+		b.WriteString(s[last:span.Start])
+		b.WriteString(span.Replacement)
+		last = span.End
+	}
+
+	b.WriteString(s[last:])
+	return b.String()
+}
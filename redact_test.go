@@ -0,0 +1,30 @@
+package redact
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestApplySpans(t *testing.T) {
+	type testCase struct {
+		input    string // string to be redacted
+		spans    []Span // spans to replace
+		expected string // expected output
+	}
+
+	cases := []testCase{
+		{"this is a test.", nil, "this is a test."},
+		{"this is a test.", []Span{{10, 14, "[redacted]"}}, "this is a [redacted]."},
+		{"this is a test.", []Span{{0, 4, "X"}, {5, 7, "Y"}, {15, 15, "!"}}, "X Y a test.!"},
+		{"héllo wörld", []Span{{0, 6, "hi"}, {7, 13, "there"}}, "hi there"},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("input=%q;spans=%v;expected=%q; ", tc.input, tc.spans, tc.expected), func(t *testing.T) {
+			got := ApplySpans(tc.input, tc.spans)
+			if tc.expected != got {
+				t.Errorf("Expected '%s', but got '%s'", tc.expected, got)
+			}
+		})
+	}
+}
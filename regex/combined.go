@@ -0,0 +1,570 @@
+package regex
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kristinjeanna/redact"
+)
+
+var (
+	errCombinedPairsSliceNil   = errors.New("regex.NewCombined: regex pairs slice must not be nil")
+	errCombinedPairsSliceEmpty = errors.New("regex.NewCombined: regex pairs slice must not be empty")
+
+	errMsgFmtCombinedRedact = "regex.CombinedRedactor.Redact: error while redacting, %w"
+)
+
+// CombinedRedactor is a redactor that finds the matches of all of its pairs
+// on the original input and then rebuilds the input once, dispatching each
+// match to the redactor of the pair that produced it.
+//
+// Pairs whose regular expression starts with literal text are matched in a
+// single scan of the input for all of those prefixes. The expressions of
+// the other pairs using the default engine are compiled into a single
+// alternation, so the input is scanned once for all of them as well, and
+// each of those pairs is then only matched within the regions found by the
+// alternation. Only pairs with other matchers are scanned separately.
+//
+// Every pair finds the same matches as its own FindAll methods would. When
+// the matches of several pairs overlap, the match of the pair appearing
+// first in the slice wins and the others are discarded.
+//
+// Unlike RegexRedactor, the output of one pair is never rescanned by the
+// pairs that follow it. Both redactors therefore produce the same result
+// whenever the matches of different pairs neither overlap nor match text
+// inserted by another pair's redactor.
+type CombinedRedactor struct {
+	pairs    []Pair
+	prefixes *prefixScanner // nil if no expression starts with literal text
+	union    *alternation   // nil if every other pair uses another matcher
+	others   []int          // indexes of the pairs using other matchers
+}
+
+// prefixScanner finds the matches of the pairs whose expressions start with
+// literal text.
+type prefixScanner struct {
+	pairs    []int            // index of the pair of each expression
+	prefixes []string         // literal prefix of each expression
+	anchored []*regexp.Regexp // each expression, anchored at the start
+	byFirst  [256][]int       // expressions by the first byte of their prefix
+}
+
+// alternation is a regular expression matching the expressions of several
+// pairs at once. Since no pair matches outside of the matches of the
+// alternation, or right at their end, those are the only regions each pair's
+// expression is tried in.
+type alternation struct {
+	re       *regexp.Regexp
+	pairs    []int            // index of the pair of each alternative
+	anchored []*regexp.Regexp // each expression, anchored at the start
+	inner    []*regexp.Regexp // each expression, anchored after one character
+	first    []*[256]bool     // bytes each expression's matches may start with
+}
+
+// match is a match of one of the pairs of a CombinedRedactor.
+type match struct {
+	pair    int   // index of the pair
+	indexes []int // match and, if available, submatch byte offsets
+}
+
+// NewCombined returns a new CombinedRedactor.
+func NewCombined(rePairs []Pair) (redact.Redactor, error) {
+	if rePairs == nil {
+		return nil, errCombinedPairsSliceNil
+	}
+
+	if len(rePairs) == 0 {
+		return nil, errCombinedPairsSliceEmpty
+	}
+
+	return newCombined(rePairs), nil
+}
+
+// newCombined returns a new CombinedRedactor for a non-empty slice of pairs.
+func newCombined(pairs []Pair) CombinedRedactor {
+	r := CombinedRedactor{pairs: pairs}
+
+	prefixes := &prefixScanner{}
+	union := &alternation{}
+	var exprs []string
+	for i, pair := range pairs {
+		stdMatcher, ok := pair.matcher.(RegexpMatcher)
+		if !ok {
+			r.others = append(r.others, i)
+			continue
+		}
+
+		expr := stdMatcher.re.String()
+		if prefix := literalPrefix(expr); prefix != "" {
+			if anchored, err := regexp.Compile(`^(?:` + expr + `)`); err == nil {
+				prefixes.add(i, prefix, anchored)
+				continue
+			}
+		}
+
+		exprs = append(exprs, "(?:"+expr+")")
+		union.pairs = append(union.pairs, i)
+	}
+
+	if len(prefixes.pairs) > 0 {
+		r.prefixes = prefixes
+	}
+
+	switch len(exprs) {
+	case 0:
+	case 1:
+		// a single expression needs no alternation
+		r.others = append(r.others, union.pairs[0])
+		sort.Ints(r.others)
+	default:
+		if union.compile(exprs) == nil {
+			r.union = union
+		} else {
+			// the alternation exceeds the limits of the regexp package, so
+			// fall back to scanning for each of its pairs separately
+			r.others = append(r.others, union.pairs...)
+			sort.Ints(r.others)
+		}
+	}
+
+	return r
+}
+
+// Redact redacts the matches of all of the pairs in s.
+func (r CombinedRedactor) Redact(s string) (string, error) {
+	spans, err := r.Detect(s)
+	if err != nil {
+		return "", err
+	}
+
+	return redact.ApplySpans(s, spans), nil
+}
+
+// Detect returns the spans of s that the pairs redact.
+func (r CombinedRedactor) Detect(s string) ([]redact.Span, error) {
+	// the matches of each pair, each sorted and not overlapping one another
+	found := make([][]match, len(r.pairs))
+	if r.prefixes != nil {
+		for _, m := range r.prefixes.findAll(s) {
+			found[m.pair] = append(found[m.pair], m)
+		}
+	}
+	if r.union != nil {
+		for _, m := range r.union.findAll(s) {
+			found[m.pair] = append(found[m.pair], m)
+		}
+	}
+	for _, i := range r.others {
+		for _, m := range r.pairs[i].findAll(s) {
+			found[i] = append(found[i], match{pair: i, indexes: m})
+		}
+	}
+
+	count := 0
+	for _, pairMatches := range found {
+		count += len(pairMatches)
+	}
+	matches := make([]match, 0, count)
+	for _, pairMatches := range found {
+		matches = append(matches, pairMatches...)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].indexes[0] != matches[j].indexes[0] {
+			return matches[i].indexes[0] < matches[j].indexes[0]
+		}
+		return matches[i].pair < matches[j].pair
+	})
+
+	if hasOverlaps(matches) {
+		// resolve the overlaps by adding the matches of one pair at a time
+		matches = nil
+		for _, pairMatches := range found {
+			matches = mergeMatches(matches, pairMatches)
+		}
+	}
+
+	var spans []redact.Span
+	last := 0
+	for _, m := range matches {
+		if m.indexes[0] < last {
+			continue // empty match at the start of an accepted match
+		}
+
+		var err error
+		spans, err = r.pairs[m.pair].appendSpans(spans, s, m.indexes)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFmtCombinedRedact, err)
+		}
+		last = m.indexes[1]
+	}
+
+	return spans, nil
+}
+
+// String returns a text representation of the redactor.
+func (r CombinedRedactor) String() string {
+	return fmt.Sprintf("{combined=true; pairs=%v}", r.pairs)
+}
+
+// literalPrefix returns the literal text that every match of expr starts
+// with. Since matching from that text on never depends on the input
+// preceding it, the expression can be matched against the rest of the input
+// at each occurrence of the prefix. Expressions starting with an anchor or a
+// word boundary therefore have no prefix.
+func literalPrefix(expr string) string {
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return ""
+	}
+
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return ""
+	}
+
+	prefix, _ := prog.Prefix()
+	return prefix
+}
+
+// add adds the expression of a pair starting with the non-empty literal
+// prefix to the scanner.
+func (p *prefixScanner) add(pair int, prefix string, anchored *regexp.Regexp) {
+	p.byFirst[prefix[0]] = append(p.byFirst[prefix[0]], len(p.pairs))
+	p.pairs = append(p.pairs, pair)
+	p.prefixes = append(p.prefixes, prefix)
+	p.anchored = append(p.anchored, anchored)
+}
+
+// findAll returns the matches of the expressions in s, sorted by start
+// offset. The matches of each expression are those its own FindAll methods
+// would report.
+func (p *prefixScanner) findAll(s string) []match {
+	var matches []match
+	next := make([]int, len(p.pairs)) // offset each expression resumes at
+
+	for i := 0; i < len(s); i++ {
+		for _, k := range p.byFirst[s[i]] {
+			if i < next[k] || !strings.HasPrefix(s[i:], p.prefixes[k]) {
+				continue
+			}
+
+			m := p.anchored[k].FindStringSubmatchIndex(s[i:])
+			if m == nil {
+				continue
+			}
+			for j := range m {
+				if m[j] >= 0 {
+					m[j] += i
+				}
+			}
+			next[k] = m[1]
+			matches = append(matches, match{pair: p.pairs[k], indexes: m})
+		}
+	}
+
+	return matches
+}
+
+// compile compiles the alternation of the expressions and the anchored
+// copies of each of them.
+func (a *alternation) compile(exprs []string) error {
+	re, err := regexp.Compile(strings.Join(exprs, "|"))
+	if err != nil {
+		return err
+	}
+
+	a.anchored = make([]*regexp.Regexp, len(exprs))
+	a.inner = make([]*regexp.Regexp, len(exprs))
+	a.first = make([]*[256]bool, len(exprs))
+	for k, expr := range exprs {
+		a.first[k] = firstBytes(expr)
+		if a.anchored[k], err = regexp.Compile(`^` + expr); err != nil {
+			return err
+		}
+		// the leading character gives assertions like \b and ^ the context
+		// preceding the position the expression is tried at
+		if a.inner[k], err = regexp.Compile(`^(?s:.)` + expr); err != nil {
+			return err
+		}
+	}
+
+	a.re = re
+	return nil
+}
+
+// findAll returns the matches of the pairs of the alternation in s. The
+// matches of each pair are sorted by start offset and are those its own
+// FindAll methods would report.
+func (a *alternation) findAll(s string) []match {
+	found := a.re.FindAllStringIndex(s, -1)
+	if found == nil {
+		return nil
+	}
+
+	// the regions, including their end, each pair's expression is tried in
+	regions := [][2]int{{found[0][0], found[0][1]}}
+	for _, m := range found[1:] {
+		if last := &regions[len(regions)-1]; m[0] <= last[1] {
+			if m[1] > last[1] {
+				last[1] = m[1]
+			}
+			continue
+		}
+		regions = append(regions, [2]int{m[0], m[1]})
+	}
+
+	var matches []match
+	for k := range a.pairs {
+		matches = a.appendPairMatches(matches, k, s, regions)
+	}
+	return matches
+}
+
+// appendPairMatches appends the matches of the expression of the k-th
+// alternative in s to matches, following the rules of the FindAll methods
+// of the regexp package for empty matches.
+func (a *alternation) appendPairMatches(matches []match, k int, s string, regions [][2]int) []match {
+	region := 0
+	for pos, prevEnd := 0, -1; pos <= len(s); {
+		m := a.matchFrom(k, s, pos, regions, &region)
+		if m == nil {
+			break
+		}
+
+		accept := true
+		if m[1] == pos {
+			// empty match, not allowed right after a previous match
+			accept = m[0] != prevEnd
+			pos += runeWidth(s, pos)
+		} else {
+			pos = m[1]
+		}
+		prevEnd = m[1]
+
+		if accept {
+			matches = append(matches, match{pair: a.pairs[k], indexes: m})
+		}
+	}
+
+	return matches
+}
+
+// matchFrom returns the first match of the expression of the k-th
+// alternative in s starting at or after pos, trying only the positions in
+// the regions from the one at index *region on.
+func (a *alternation) matchFrom(k int, s string, pos int, regions [][2]int, region *int) []int {
+	for ; *region < len(regions); *region++ {
+		start, end := regions[*region][0], regions[*region][1]
+		if end < pos {
+			continue
+		}
+
+		if start < pos {
+			start = pos
+		}
+		first := a.first[k]
+		for i := start; i <= end; i += runeWidth(s, i) {
+			if first != nil && (i == len(s) || !first[s[i]]) {
+				continue
+			}
+			if m := a.matchAt(k, s, i); m != nil {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// matchAt returns the match of the expression of the k-th alternative in s
+// starting at byte offset i, if any.
+func (a *alternation) matchAt(k int, s string, i int) []int {
+	if i == 0 {
+		return a.anchored[k].FindStringSubmatchIndex(s)
+	}
+
+	_, width := utf8.DecodeLastRuneInString(s[:i])
+	base := i - width
+	m := a.inner[k].FindStringSubmatchIndex(s[base:])
+	if m == nil {
+		return nil
+	}
+	for j := range m {
+		if m[j] >= 0 {
+			m[j] += base
+		}
+	}
+	m[0] = i
+	return m
+}
+
+// firstBytes returns the set of bytes every non-empty match of expr starts
+// with, or nil if expr may match the empty string or its first bytes are
+// not known.
+func firstBytes(expr string) *[256]bool {
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil
+	}
+
+	var set [256]bool
+	visited := make([]bool, len(prog.Inst))
+	pending := []uint32{uint32(prog.Start)}
+	for len(pending) > 0 {
+		pc := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if visited[pc] {
+			continue
+		}
+		visited[pc] = true
+
+		inst := prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			pending = append(pending, inst.Out, inst.Arg)
+		case syntax.InstCapture, syntax.InstNop, syntax.InstEmptyWidth:
+			pending = append(pending, inst.Out)
+		case syntax.InstRune1, syntax.InstRune:
+			addFirstBytes(&set, inst)
+		default:
+			// matches anything or nothing at all
+			return nil
+		}
+	}
+
+	return &set
+}
+
+// addFirstBytes adds the first bytes of the runes matched by inst to set.
+func addFirstBytes(set *[256]bool, inst syntax.Inst) {
+	runes := inst.Rune
+	if len(runes) == 1 {
+		runes = []rune{runes[0], runes[0]}
+	}
+	fold := syntax.Flags(inst.Arg)&syntax.FoldCase != 0
+
+	for i := 0; i+1 < len(runes); i += 2 {
+		for r := runes[i]; r <= runes[i+1]; r++ {
+			if r >= utf8.RuneSelf {
+				// any byte that may start a multibyte rune or be invalid
+				for c := utf8.RuneSelf; c < len(set); c++ {
+					set[c] = true
+				}
+				break
+			}
+
+			set[r] = true
+			for f := unicode.SimpleFold(r); fold && f != r; f = unicode.SimpleFold(f) {
+				if f < utf8.RuneSelf {
+					set[f] = true
+				} else {
+					for c := utf8.RuneSelf; c < len(set); c++ {
+						set[c] = true
+					}
+				}
+			}
+		}
+	}
+}
+
+// runeWidth returns the width of the rune at byte offset i of s, or 1 at
+// the end of s.
+func runeWidth(s string, i int) int {
+	if i >= len(s) {
+		return 1
+	}
+	_, width := utf8.DecodeRuneInString(s[i:])
+	return width
+}
+
+// mergeMatches returns the accepted matches together with those of the
+// candidates that do not overlap any of them, sorted by start offset. Both
+// slices must be sorted by start offset and free of overlaps.
+func mergeMatches(accepted, candidates []match) []match {
+	if len(accepted) == 0 {
+		return candidates
+	}
+	if len(candidates) == 0 {
+		return accepted
+	}
+
+	merged := make([]match, 0, len(accepted)+len(candidates))
+	i := 0
+	for _, m := range candidates {
+		start, end := m.indexes[0], m.indexes[1]
+
+		// first accepted match starting after m
+		pos := i + sort.Search(len(accepted)-i, func(j int) bool {
+			return accepted[i+j].indexes[0] > start
+		})
+
+		if conflicts(accepted, pos, start, end) {
+			continue
+		}
+
+		merged = append(merged, accepted[i:pos]...)
+		merged = append(merged, m)
+		i = pos
+	}
+
+	return append(merged, accepted[i:]...)
+}
+
+// hasOverlaps reports whether any of the matches, sorted by start offset,
+// overlap one another.
+func hasOverlaps(matches []match) bool {
+	var last []int // last non-empty match
+	for _, m := range matches {
+		if last != nil && overlaps(last, m.indexes[0], m.indexes[1]) {
+			return true
+		}
+		if m.indexes[0] < m.indexes[1] {
+			last = m.indexes
+		}
+	}
+	return false
+}
+
+// conflicts reports whether the byte range [start, end) overlaps one of the
+// accepted matches, where pos is the index of the first accepted match
+// starting after start. An empty range only conflicts with a match it lies
+// strictly inside of.
+func conflicts(accepted []match, pos, start, end int) bool {
+	for i := pos - 1; i >= 0; i-- {
+		if overlaps(accepted[i].indexes, start, end) {
+			return true
+		}
+		if accepted[i].indexes[0] < accepted[i].indexes[1] {
+			break // the matches before a non-empty one end before it starts
+		}
+	}
+
+	for i := pos; i < len(accepted) && accepted[i].indexes[0] <= end; i++ {
+		if overlaps(accepted[i].indexes, start, end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// overlaps reports whether the match m overlaps the byte range [start, end).
+func overlaps(m []int, start, end int) bool {
+	switch {
+	case start == end:
+		return m[0] < start && start < m[1]
+	case m[0] == m[1]:
+		return start < m[0] && m[0] < end
+	default:
+		return start < m[1] && m[0] < end
+	}
+}
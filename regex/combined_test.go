@@ -0,0 +1,201 @@
+package regex
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/blackout"
+	"github.com/kristinjeanna/redact/middle"
+	"github.com/kristinjeanna/redact/simple"
+	"github.com/kristinjeanna/redact/url"
+)
+
+func TestCombined_matchesSequential(t *testing.T) {
+	ssn, _ := NewPair(blackout.New("X"), SSNRegex)
+	auth, _ := NewPairUsingSimple("${1}[redacted]", AuthHeaderRegex)
+	token, _ := NewGroupPair(middle.New(), `(?i)token=(?P<token>\w+)`, "token")
+	word, _ := NewPairUsingSimple("[word]", `\bsecret\b`)
+	swap, _ := NewPairUsingSimple("${2}${1}", `\b([A-Z])(\d)\b`)
+	key, _ := NewGroupPair(simple.New("***"), `key=(\w+)(?:,(\w+))?`, "2")
+	pairs := []Pair{*ssn, *auth, *token, *word, *swap, *key}
+
+	inputs := []string{
+		"the SSN is 123-45-6789",
+		"Authorization: Bearer abc\nTOKEN=abcdefghijklmnop and a secret",
+		"secret 987654321 token=abc secretive",
+		"nothing to redact",
+		"key=abc,def swaps A1 and key=ghi then B2, key=jkl,mno",
+		"",
+	}
+
+	sequential, err := New(pairs)
+	if err != nil {
+		t.Error(err)
+	}
+	combined, err := NewCombined(pairs)
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, input := range inputs {
+		t.Run(fmt.Sprintf("input=%q; ", input), func(t *testing.T) {
+			expected, err := sequential.Redact(input)
+			if err != nil {
+				t.Error(err)
+			}
+			got, err := combined.Redact(input)
+			if err != nil {
+				t.Error(err)
+			}
+			if expected != got {
+				t.Errorf("Expected '%s', but got '%s'", expected, got)
+			}
+		})
+	}
+}
+
+func TestCombined_priority(t *testing.T) {
+	digits, _ := NewPairUsingSimple("[digits]", `\d+`)
+	phone, _ := NewPairUsingSimple("[phone]", `\d{3}-\d{4}`)
+
+	type testCase struct {
+		pairs    []Pair // the regex/replacement pairs
+		expected string // expected output
+	}
+
+	literal, _ := NewPairWithMatcher(simple.New("[literal]"), NewLiteralMatcher("555-12"))
+	empty, _ := NewPairUsingSimple("|", `x*`)
+	call, _ := NewPairUsingSimple("[call]", `call 5`)
+	anchored, _ := NewPairUsingSimple("[anchored]", `^555`)
+	ells, _ := NewPairUsingSimple("[ells]", `l+ \d`)
+	tail, _ := NewPairUsingSimple("[tail]", `\b\d{4}\b`)
+
+	cases := []testCase{
+		{[]Pair{*phone, *digits}, "call [phone] or [digits]"},
+		{[]Pair{*digits, *phone}, "call [digits]-[digits] or [digits]"},
+		{[]Pair{*literal, *digits}, "call [literal]34 or [digits]"},
+		{[]Pair{*digits, *literal}, "call [digits]-[digits] or [digits]"},
+		{[]Pair{*phone, *empty}, "|c|a|l|l| [phone]| |o|r| |4|2|"},
+		{[]Pair{*phone, *call}, "call [phone] or 42"},
+		{[]Pair{*call, *phone}, "[call]55-1234 or 42"},
+		{[]Pair{*anchored, *digits}, "call [digits]-[digits] or [digits]"},
+		{[]Pair{*phone, *ells}, "call [phone] or 42"},
+		{[]Pair{*ells, *phone}, "ca[ells]55-1234 or 42"},
+		{[]Pair{*tail, *ells, *phone}, "ca[ells]55-[tail] or 42"},
+		{[]Pair{*tail, *phone}, "call 555-[tail] or 42"},
+		{[]Pair{*phone, *tail}, "call [phone] or 42"},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("pairs=%q;expected=%q; ", tc.pairs, tc.expected), func(t *testing.T) {
+			r, err := NewCombined(tc.pairs)
+			if err != nil {
+				t.Error(err)
+			}
+			got, err := r.Redact("call 555-1234 or 42")
+			if err != nil {
+				t.Error(err)
+			}
+			if tc.expected != got {
+				t.Errorf("Expected '%s', but got '%s'", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCombined_errors(t *testing.T) {
+	_, err := NewCombined(nil)
+	if err != errCombinedPairsSliceNil {
+		t.Errorf("Expected '%v', but got '%v'", errCombinedPairsSliceNil, err)
+	}
+
+	_, err = NewCombined([]Pair{})
+	if err != errCombinedPairsSliceEmpty {
+		t.Errorf("Expected '%v', but got '%v'", errCombinedPairsSliceEmpty, err)
+	}
+
+	for _, pair := range []*Pair{
+		mustPair(NewPair(url.New("foo", nil), "[is]")),
+		mustPair(NewGroupPair(url.New("foo", nil), "(is)", "1")),
+	} {
+		r, err := NewCombined([]Pair{*pair})
+		if err != nil {
+			t.Error(err)
+		}
+		_, err = r.Redact("this string contains redacted")
+		if err == nil {
+			t.Error("Expected an error, but got nil")
+		}
+	}
+}
+
+func TestCombined_String(t *testing.T) {
+	pair, _ := NewPairUsingSimple("[redacted]", "test")
+	redactor, _ := NewCombined([]Pair{*pair})
+
+	expected := `{combined=true; pairs=[{regex="test"; redactor={replacement="[redacted]"}}]}`
+	got := fmt.Sprint(redactor)
+
+	if expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
+
+func mustPair(pair *Pair, err error) *Pair {
+	if err != nil {
+		panic(err)
+	}
+	return pair
+}
+
+func benchmarkPairs(count int) []Pair {
+	pairs := make([]Pair, 0, count)
+	pairs = append(pairs, *mustPair(NewPair(blackout.New("X"), SSNRegex)))
+	for i := 1; i < count; i++ {
+		pairs = append(pairs, *mustPair(NewPairUsingSimple("[redacted]", fmt.Sprintf(`key%d=\w+`, i))))
+	}
+	return pairs
+}
+
+func benchmarkCombinedInput() string {
+	var b strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&b, "user %d has SSN %03d-45-6789 and key%d=abc; ", i, i%1000, i%40)
+	}
+	return b.String()
+}
+
+func BenchmarkRedact_sequential40Pairs(b *testing.B) {
+	r, _ := New(benchmarkPairs(40))
+	benchmarkRedact(b, r, benchmarkCombinedInput())
+}
+
+func BenchmarkRedact_combined40Pairs(b *testing.B) {
+	r, _ := NewCombined(benchmarkPairs(40))
+	benchmarkRedact(b, r, benchmarkCombinedInput())
+}
+
+func TestDetect(t *testing.T) {
+	token, _ := NewGroupPair(simple.New("X"), `token=(\w+) user=(\w+)`, "1", "2")
+	ssn, _ := NewPairUsingSimple("[${1}]", SSNRegex)
+	r, err := New([]Pair{*token, *ssn})
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []redact.Span{
+		{Start: 6, End: 9, Replacement: "X"},
+		{Start: 15, End: 18, Replacement: "X"},
+		{Start: 23, End: 34, Replacement: "[123-45-6789]"},
+	}
+	got, err := r.(redact.Detector).Detect("token=abc user=bob ssn 123-45-6789")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected '%v', but got '%v'", expected, got)
+	}
+}
@@ -0,0 +1,124 @@
+package regex
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Matcher is the pattern matching engine used by a Pair to find the text
+// to redact. The default implementation is backed by the standard library
+// regexp package; other engines, such as ones supporting lookarounds, can be
+// plugged in via NewPairWithMatcher.
+type Matcher interface {
+
+	// FindAllIndex returns the byte offsets of successive non-overlapping
+	// matches in s, following the conventions of
+	// (*regexp.Regexp).FindAllStringIndex. If n >= 0, at most n matches are
+	// returned. A nil result indicates no match.
+	FindAllIndex(s string, n int) [][]int
+
+	// Match reports whether s contains any match.
+	Match(s string) bool
+}
+
+// SubmatchMatcher is a Matcher that also reports the locations of capture
+// groups. It is required for pairs created via NewGroupPairWithMatcher.
+type SubmatchMatcher interface {
+	Matcher
+
+	// FindAllSubmatchIndex returns the byte offsets of successive
+	// non-overlapping matches and their capture groups in s, following the
+	// conventions of (*regexp.Regexp).FindAllStringSubmatchIndex.
+	FindAllSubmatchIndex(s string, n int) [][]int
+
+	// NumSubexp returns the number of capture groups.
+	NumSubexp() int
+
+	// SubexpIndex returns the index of the capture group with the given
+	// name, or -1 if there is no such group.
+	SubexpIndex(name string) int
+}
+
+// RegexpMatcher is the default Matcher, backed by a compiled *regexp.Regexp.
+type RegexpMatcher struct {
+	re *regexp.Regexp
+}
+
+// NewRegexpMatcher returns a new RegexpMatcher for the compiled regex.
+func NewRegexpMatcher(re *regexp.Regexp) SubmatchMatcher {
+	return RegexpMatcher{re: re}
+}
+
+// FindAllIndex returns the byte offsets of successive matches in s.
+func (m RegexpMatcher) FindAllIndex(s string, n int) [][]int {
+	return m.re.FindAllStringIndex(s, n)
+}
+
+// Match reports whether s contains any match of the regex.
+func (m RegexpMatcher) Match(s string) bool {
+	return m.re.MatchString(s)
+}
+
+// FindAllSubmatchIndex returns the byte offsets of successive matches and
+// their capture groups in s.
+func (m RegexpMatcher) FindAllSubmatchIndex(s string, n int) [][]int {
+	return m.re.FindAllStringSubmatchIndex(s, n)
+}
+
+// NumSubexp returns the number of capture groups in the regex.
+func (m RegexpMatcher) NumSubexp() int {
+	return m.re.NumSubexp()
+}
+
+// SubexpIndex returns the index of the named capture group, or -1.
+func (m RegexpMatcher) SubexpIndex(name string) int {
+	return m.re.SubexpIndex(name)
+}
+
+// String returns the source text of the regex.
+func (m RegexpMatcher) String() string {
+	return m.re.String()
+}
+
+// LiteralMatcher is a Matcher for a fixed string. It avoids the overhead of
+// a regex engine when the text to redact is known in advance.
+type LiteralMatcher struct {
+	literal string
+}
+
+// NewLiteralMatcher returns a new LiteralMatcher for the literal string.
+func NewLiteralMatcher(literal string) Matcher {
+	return LiteralMatcher{literal: literal}
+}
+
+// FindAllIndex returns the byte offsets of successive non-overlapping
+// occurrences of the literal in s.
+func (m LiteralMatcher) FindAllIndex(s string, n int) [][]int {
+	if len(m.literal) == 0 || n == 0 {
+		return nil
+	}
+
+	var indexes [][]int
+	for pos := 0; n < 0 || len(indexes) < n; {
+		idx := strings.Index(s[pos:], m.literal)
+		if idx < 0 {
+			break
+		}
+		start := pos + idx
+		pos = start + len(m.literal)
+		indexes = append(indexes, []int{start, pos})
+	}
+
+	return indexes
+}
+
+// Match reports whether s contains the literal.
+func (m LiteralMatcher) Match(s string) bool {
+	return len(m.literal) > 0 && strings.Contains(s, m.literal)
+}
+
+// String returns a text representation of the matcher.
+func (m LiteralMatcher) String() string {
+	return fmt.Sprintf("literal:%s", m.literal)
+}
@@ -0,0 +1,155 @@
+package regex
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kristinjeanna/redact/blackout"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+// notPrecededByMatcher emulates a negative lookbehind: it matches the regex
+// only where the match is not immediately preceded by the prefix.
+type notPrecededByMatcher struct {
+	re     *regexp.Regexp
+	prefix string
+}
+
+func (m notPrecededByMatcher) FindAllIndex(s string, n int) [][]int {
+	var indexes [][]int
+	for _, loc := range m.re.FindAllStringIndex(s, -1) {
+		if n >= 0 && len(indexes) >= n {
+			break
+		}
+		if !strings.HasSuffix(s[:loc[0]], m.prefix) {
+			indexes = append(indexes, loc)
+		}
+	}
+	return indexes
+}
+
+func (m notPrecededByMatcher) Match(s string) bool {
+	return m.FindAllIndex(s, 1) != nil
+}
+
+func TestNewPairWithMatcher(t *testing.T) {
+	matcher := notPrecededByMatcher{re: regexp.MustCompile(`\d+`), prefix: "order #"}
+	pair, err := NewPairWithMatcher(blackout.New("X"), matcher)
+	if err != nil {
+		t.Error(err)
+	}
+
+	r, err := New([]Pair{*pair})
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := "order #12345 for account XXXX"
+	got, err := r.Redact("order #12345 for account 9876")
+	if err != nil {
+		t.Error(err)
+	}
+	if expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
+
+func TestNewPairWithMatcher_err(t *testing.T) {
+	_, err := NewPairWithMatcher(simple.New("X"), nil)
+	if err != errMatcherNil {
+		t.Errorf("Expected '%v', but got '%v'", errMatcherNil, err)
+	}
+
+	_, err = NewGroupPairWithMatcher(simple.New("X"), nil, "1")
+	if err != errMatcherNil {
+		t.Errorf("Expected '%v', but got '%v'", errMatcherNil, err)
+	}
+
+	_, err = NewGroupPairWithMatcher(simple.New("X"), NewRegexpMatcher(regexp.MustCompile(`(a)`)))
+	if err != errGroupsEmpty {
+		t.Errorf("Expected '%v', but got '%v'", errGroupsEmpty, err)
+	}
+}
+
+func TestNewGroupPairWithMatcher(t *testing.T) {
+	matcher := NewRegexpMatcher(regexp.MustCompile(`token=(?P<token>\w+)`))
+	pair, err := NewGroupPairWithMatcher(simple.New("$1"), matcher, "token")
+	if err != nil {
+		t.Error(err)
+	}
+
+	r, err := New([]Pair{*pair})
+	if err != nil {
+		t.Error(err)
+	}
+
+	// templates are not expanded for group pairs
+	expected := "token=$1 user=bob"
+	got, err := r.Redact("token=abc123 user=bob")
+	if err != nil {
+		t.Error(err)
+	}
+	if expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
+
+func TestLiteralMatcher(t *testing.T) {
+	type testCase struct {
+		literal  string  // the literal to find
+		input    string  // string to search
+		n        int     // maximum number of matches
+		expected [][]int // expected match offsets
+	}
+
+	cases := []testCase{
+		{"ab", "ab xab abab", -1, [][]int{{0, 2}, {4, 6}, {7, 9}, {9, 11}}},
+		{"ab", "ab xab abab", 2, [][]int{{0, 2}, {4, 6}}},
+		{"aa", "aaa", -1, [][]int{{0, 2}}},
+		{"ab", "xyz", -1, nil},
+		{"ab", "ab", 0, nil},
+		{"", "ab", -1, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("literal=%q;input=%q;n=%d; ", tc.literal, tc.input, tc.n), func(t *testing.T) {
+			m := NewLiteralMatcher(tc.literal)
+			got := m.FindAllIndex(tc.input, tc.n)
+			if !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("Expected '%v', but got '%v'", tc.expected, got)
+			}
+			if m.Match(tc.input) != (len(tc.literal) > 0 && strings.Contains(tc.input, tc.literal)) {
+				t.Errorf("Match returned an unexpected result")
+			}
+		})
+	}
+}
+
+func TestLiteralMatcher_simpleRedactor(t *testing.T) {
+	pair, err := NewPairWithMatcher(simple.New("${1}"), NewLiteralMatcher("secret"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	r, err := New([]Pair{*pair})
+	if err != nil {
+		t.Error(err)
+	}
+
+	// the replacement is used literally with non-stdlib matchers
+	expected := `{pairs=[{regex="literal:secret"; redactor={replacement="${1}"}}]}`
+	if got := fmt.Sprint(r); expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+
+	got, err := r.Redact("a secret and another secret")
+	if err != nil {
+		t.Error(err)
+	}
+	if got != "a ${1} and another ${1}" {
+		t.Errorf("Expected '%s', but got '%s'", "a ${1} and another ${1}", got)
+	}
+}
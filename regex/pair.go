@@ -4,15 +4,19 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 
 	"github.com/kristinjeanna/redact"
 	"github.com/kristinjeanna/redact/simple"
 )
 
 var (
-	errRegexEmpty = errors.New("regex.NewPair: regex is required")
+	errRegexEmpty  = errors.New("regex.NewPair: regex is required")
+	errMatcherNil  = errors.New("regex.NewPairWithMatcher: matcher must not be nil")
+	errGroupsEmpty = errors.New("regex.NewGroupPair: at least one capture group is required")
 
 	errMsgFmtCompileFailure = "regex.NewPair: regex failed to compile, %w"
+	errMsgFmtUnknownGroup   = "regex.NewGroupPair: regex has no capture group %q"
 )
 
 // Pair represents a regular expression and the corresponding
@@ -20,13 +24,63 @@ var (
 type Pair struct {
 	redactor redact.Redactor
 	regex    string
-	compiled *regexp.Regexp
+	matcher  Matcher
+	name     string
+
+	// groups holds the names or numbers of the capture groups to redact,
+	// and groupIndexes the corresponding subexpression indexes. When empty,
+	// the entire match is redacted.
+	groups       []string
+	groupIndexes []int
+
+	// allowed holds the allow-list predicates checked against the text of
+	// each match or capture group before redacting it.
+	allowed []func(string) bool
 }
 
 func (p Pair) String() string {
+	if len(p.groups) > 0 {
+		return fmt.Sprintf("{regex=%q; groups=%q; redactor=%v}", p.regex, p.groups, p.redactor)
+	}
 	return fmt.Sprintf("{regex=%q; redactor=%v}", p.regex, p.redactor)
 }
 
+// Named returns a copy of the pair with the specified name. The name
+// identifies the pair in the metrics reported by a RegexRedactor.
+func (p Pair) Named(name string) Pair {
+	p.name = name
+	return p
+}
+
+// Except returns a copy of the pair that leaves the text of a match
+// unchanged if it satisfies any of the allow-list predicates, such as
+// conditional.Equals or conditional.MatchesFully. The predicates are checked
+// against each match or, for pairs with capture groups, each group to redact,
+// rather than against the entire input.
+func (p Pair) Except(allowed ...func(string) bool) Pair {
+	p.allowed = append(append([]func(string) bool{}, p.allowed...), allowed...)
+	return p
+}
+
+// isAllowed reports whether s satisfies any of the allow-list predicates of
+// the pair.
+func (p Pair) isAllowed(s string) bool {
+	for _, allowed := range p.allowed {
+		if allowed != nil && allowed(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the name of the pair, which defaults to its regex.
+func (p Pair) Name() string {
+	if p.name != "" {
+		return p.name
+	}
+	return p.regex
+}
+
 // NewPair returns a new Pair.
 func NewPair(redactor redact.Redactor, regex string) (*Pair, error) {
 	if len(regex) == 0 {
@@ -40,7 +94,21 @@ func NewPair(redactor redact.Redactor, regex string) (*Pair, error) {
 	return &Pair{
 		redactor: redactor,
 		regex:    regex,
-		compiled: rec,
+		matcher:  NewRegexpMatcher(rec),
+	}, nil
+}
+
+// NewPairWithMatcher returns a new Pair that uses the specified matching
+// engine in place of the standard library regexp package.
+func NewPairWithMatcher(redactor redact.Redactor, matcher Matcher) (*Pair, error) {
+	if matcher == nil {
+		return nil, errMatcherNil
+	}
+
+	return &Pair{
+		redactor: redactor,
+		regex:    fmt.Sprint(matcher),
+		matcher:  matcher,
 	}, nil
 }
 
@@ -48,3 +116,67 @@ func NewPair(redactor redact.Redactor, regex string) (*Pair, error) {
 func NewPairUsingSimple(replacement string, regex string) (*Pair, error) {
 	return NewPair(simple.New(replacement), regex)
 }
+
+// NewGroupPair returns a new Pair whose redactor is applied only to the
+// text of the specified capture groups of each match, leaving the rest of
+// the match intact. Groups are identified by name or by number, where "0"
+// denotes the entire match.
+func NewGroupPair(redactor redact.Redactor, regex string, groups ...string) (*Pair, error) {
+	if len(groups) == 0 {
+		return nil, errGroupsEmpty
+	}
+
+	pair, err := NewPair(redactor, regex)
+	if err != nil {
+		return nil, err
+	}
+
+	return withGroups(pair, pair.matcher.(SubmatchMatcher), groups)
+}
+
+// NewGroupPairWithMatcher returns a new Pair like NewGroupPair but that uses
+// the specified matching engine in place of the standard library regexp
+// package.
+func NewGroupPairWithMatcher(redactor redact.Redactor, matcher SubmatchMatcher, groups ...string) (*Pair, error) {
+	if len(groups) == 0 {
+		return nil, errGroupsEmpty
+	}
+
+	pair, err := NewPairWithMatcher(redactor, matcher)
+	if err != nil {
+		return nil, err
+	}
+
+	return withGroups(pair, matcher, groups)
+}
+
+// withGroups resolves the capture groups against the matcher and sets them
+// on the pair.
+func withGroups(pair *Pair, matcher SubmatchMatcher, groups []string) (*Pair, error) {
+	indexes := make([]int, 0, len(groups))
+	for _, g := range groups {
+		idx, ok := groupIndex(matcher, g)
+		if !ok {
+			return nil, fmt.Errorf(errMsgFmtUnknownGroup, g)
+		}
+		indexes = append(indexes, idx)
+	}
+
+	pair.groups = groups
+	pair.groupIndexes = indexes
+	return pair, nil
+}
+
+// groupIndex returns the subexpression index of the capture group
+// identified by name or number.
+func groupIndex(matcher SubmatchMatcher, group string) (int, bool) {
+	if idx := matcher.SubexpIndex(group); idx >= 0 {
+		return idx, true
+	}
+
+	n, err := strconv.Atoi(group)
+	if err != nil || n < 0 || n > matcher.NumSubexp() {
+		return 0, false
+	}
+	return n, true
+}
@@ -3,6 +3,8 @@ package regex
 import (
 	"fmt"
 	"testing"
+
+	"github.com/kristinjeanna/redact/simple"
 )
 
 func TestNewPairUsingSimple_err(t *testing.T) {
@@ -32,3 +34,36 @@ func TestString(t *testing.T) {
 		t.Errorf("Expected '%s', but got '%s'", expected, got)
 	}
 }
+
+func TestNewGroupPair_err(t *testing.T) {
+	_, err := NewGroupPair(simple.New("X"), `(a)(b)`)
+	if err != errGroupsEmpty {
+		t.Errorf("Expected '%v', but got '%v'", errGroupsEmpty, err)
+	}
+
+	_, err = NewGroupPair(simple.New("X"), "", "1")
+	if err != errRegexEmpty {
+		t.Errorf("Expected '%v', but got '%v'", errRegexEmpty, err)
+	}
+
+	for _, group := range []string{"3", "-1", "nope", ""} {
+		_, err = NewGroupPair(simple.New("X"), `(a)(?P<name>b)`, group)
+		if err == nil {
+			t.Errorf("Expected an error for group %q, but got nil", group)
+		}
+	}
+}
+
+func TestPairString_withGroups(t *testing.T) {
+	pair, err := NewGroupPair(simple.New("X"), `(?P<user>\w+):(?P<secret>\w+)`, "secret", "1")
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := `{regex="(?P<user>\\w+):(?P<secret>\\w+)"; groups=["secret" "1"]; redactor={replacement="X"}}`
+	got := pair.String()
+
+	if expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
@@ -3,8 +3,12 @@ package regex
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/metrics"
 	"github.com/kristinjeanna/redact/simple"
 )
 
@@ -12,14 +16,14 @@ const (
 	// Regex for an HTTP authorization header
 	AuthHeaderRegex string = `(?i)(Authorization[\s]*:[\s]*).*`
 
-	// Regex for a US Social Security Number
+	// Regex for a US Social Security Number. Matches are not validated; see
+	// the ids package for a validating alternative.
 	SSNRegex string = `(\d{3}-?\d{2}-?\d{4})`
 )
 
 var (
-	errRePairsSliceNil         = errors.New("regex.New: regex pairs slice must not be nil")
-	errRePairsSliceEmpty       = errors.New("regex.New: regex pairs slice must not be empty")
-	errRegexMatchesReplacement = errors.New("regex.RegexRedactor.Redact: regex must not match replacement text returned from the pair's redactor")
+	errRePairsSliceNil   = errors.New("regex.New: regex pairs slice must not be nil")
+	errRePairsSliceEmpty = errors.New("regex.New: regex pairs slice must not be empty")
 
 	errMsgFmtRedactFailure = "regex.RegexRedactor.Redact: error while redacting, %w"
 )
@@ -29,7 +33,16 @@ var (
 // of replacement strings and regular expressions can be specified to chain
 // the behavior.
 type RegexRedactor struct {
-	pairs []Pair
+	pairs    []Pair
+	hook     metrics.Hook
+	combined *lazyCombined // used by Detect
+}
+
+// lazyCombined is a CombinedRedactor built on first use, so that redactors
+// only used for Redact don't pay for compiling it.
+type lazyCombined struct {
+	once     sync.Once
+	redactor CombinedRedactor
 }
 
 // New returns a new RegexRedactor.
@@ -42,7 +55,22 @@ func New(rePairs []Pair) (redact.Redactor, error) {
 		return nil, errRePairsSliceEmpty
 	}
 
-	return RegexRedactor{pairs: rePairs}, nil
+	return RegexRedactor{pairs: rePairs, combined: &lazyCombined{}}, nil
+}
+
+// NewFromOptions returns a new RegexRedactor with the provided options.
+func NewFromOptions(rePairs []Pair, opts ...Option) (redact.Redactor, error) {
+	redactor, err := New(rePairs)
+	if err != nil {
+		return nil, err
+	}
+
+	r := redactor.(RegexRedactor)
+	for _, o := range opts {
+		o(&r)
+	}
+
+	return r, nil
 }
 
 // Redact simply returns the replacement text for any string passed to it.
@@ -50,55 +78,155 @@ func (r RegexRedactor) Redact(s string) (string, error) {
 	src := s
 
 	for _, pair := range r.pairs {
-		switch pair.redactor.(type) {
-		case simple.SimpleRedactor:
-			repl, _ := pair.redactor.Redact("")
-			src = pair.compiled.ReplaceAllString(src, repl)
-		default:
-			indexes := pair.compiled.FindAllStringIndex(src, -1)
-			count := len(indexes) // infinite loop prevention
-
-			for indexes != nil {
-				loc := indexes[0]
-				found := src[loc[0]:loc[1]]
-				repl, err := pair.redactor.Redact(found)
-				if err != nil {
-					return "", fmt.Errorf(errMsgFmtRedactFailure, err)
-				}
-
-				src = replaceSubstring(src, repl, loc[0], loc[1])
-				indexes = pair.compiled.FindAllStringIndex(src, -1)
-
-				// the length of indexes should only decrease with each iteration
-				// if it doesn't, that means the regex matched on the replacement text;
-				// return error here to prevent infinite loop
-				if len(indexes) >= count {
-					return "", errRegexMatchesReplacement
-				}
-				count = len(indexes)
-			}
+		var start time.Time
+		if r.hook != nil {
+			start = time.Now()
+		}
+
+		out, matches, err := pair.redact(src, r.hook != nil)
+		if r.hook != nil {
+			r.hook.OnRedact(pair.Name(), len(src), len(out), matches, time.Since(start), err)
+		}
+		if err != nil {
+			return "", fmt.Errorf(errMsgFmtRedactFailure, err)
 		}
+		src = out
 	}
 
 	return src, nil
 }
 
+// Detect returns the spans of s that the pairs redact. Unlike Redact, every
+// pair is matched against the original input, and overlapping matches of
+// different pairs are resolved as with CombinedRedactor.
+func (r RegexRedactor) Detect(s string) ([]redact.Span, error) {
+	r.combined.once.Do(func() {
+		r.combined.redactor = newCombined(r.pairs)
+	})
+	return r.combined.redactor.Detect(s)
+}
+
 // String returns a text representation of the redactor.
 func (r RegexRedactor) String() string {
 	return fmt.Sprintf("{pairs=%v}", r.pairs)
 }
 
-func replaceSubstring(in string, repl string, from int, to int) string {
-	inRunes := []rune(in)
-	replRunes := []rune(repl)
-	outRunes := make([]rune, 0)
+// redact redacts the matches of the pair in s. If countMatches is true, it
+// also returns the number of redacted spans, which otherwise may be 0.
+func (p Pair) redact(s string, countMatches bool) (string, int, error) {
+	_, isSimple := p.redactor.(simple.SimpleRedactor)
+	stdMatcher, isStd := p.matcher.(RegexpMatcher)
 
-	if from != 0 {
-		outRunes = append(outRunes, inRunes[:from]...)
+	if isSimple && isStd && len(p.groupIndexes) == 0 && len(p.allowed) == 0 && !countMatches {
+		// capture group templates are only supported by the stdlib engine
+		repl, _ := p.redactor.Redact("")
+		return stdMatcher.re.ReplaceAllString(s, repl), 0, nil
 	}
 
-	outRunes = append(outRunes, replRunes...)
-	outRunes = append(outRunes, inRunes[to:]...)
+	spans, err := p.detect(s)
+	if err != nil {
+		return "", 0, err
+	}
+	return redact.ApplySpans(s, spans), len(spans), nil
+}
 
-	return string(outRunes)
+// detect returns the spans of s that the pair redacts.
+func (p Pair) detect(s string) ([]redact.Span, error) {
+	var spans []redact.Span
+	for _, m := range p.findAll(s) {
+		var err error
+		spans, err = p.appendSpans(spans, s, m)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return spans, nil
+}
+
+// findAll returns the matches of the pair in s, including submatch offsets
+// when the pair needs them for capture groups or templates.
+func (p Pair) findAll(s string) [][]int {
+	if p.usesSubmatches() {
+		return p.matcher.(SubmatchMatcher).FindAllSubmatchIndex(s, -1)
+	}
+	return p.matcher.FindAllIndex(s, -1)
+}
+
+// usesSubmatches reports whether redacting a match of the pair requires the
+// offsets of its capture groups.
+func (p Pair) usesSubmatches() bool {
+	_, isSimple := p.redactor.(simple.SimpleRedactor)
+	_, isStd := p.matcher.(RegexpMatcher)
+	return len(p.groupIndexes) > 0 || (isSimple && isStd)
+}
+
+// appendSpans appends the spans that replace the match m of the pair in s
+// to spans. For pairs with capture groups, each selected group yields a
+// span; when selected groups overlap, only the outermost group is redacted.
+// Otherwise the entire match yields a single span.
+func (p Pair) appendSpans(spans []redact.Span, s string, m []int) ([]redact.Span, error) {
+	if len(p.groupIndexes) == 0 {
+		if p.isAllowed(s[m[0]:m[1]]) {
+			return spans, nil
+		}
+
+		var repl string
+		if stdMatcher, ok := p.matcher.(RegexpMatcher); ok && p.usesSubmatches() {
+			template, _ := p.redactor.Redact("")
+			repl = string(stdMatcher.re.ExpandString(nil, template, s, m))
+		} else {
+			var err error
+			repl, err = p.redactor.Redact(s[m[0]:m[1]])
+			if err != nil {
+				return nil, err
+			}
+		}
+		return append(spans, redact.Span{Start: m[0], End: m[1], Replacement: repl}), nil
+	}
+
+	groups := make([][2]int, 0, len(p.groupIndexes))
+	for _, g := range p.groupIndexes {
+		if m[2*g] >= 0 { // group participated in the match
+			groups = append(groups, [2]int{m[2*g], m[2*g+1]})
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i][0] != groups[j][0] {
+			return groups[i][0] < groups[j][0]
+		}
+		return groups[i][1] > groups[j][1]
+	})
+
+	last := m[0]
+	for _, g := range groups {
+		if g[0] < last {
+			continue // nested in or overlapping an already redacted group
+		}
+		if p.isAllowed(s[g[0]:g[1]]) {
+			last = g[1] // also leave the groups nested in it unchanged
+			continue
+		}
+		repl, err := p.redactor.Redact(s[g[0]:g[1]])
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, redact.Span{Start: g[0], End: g[1], Replacement: repl})
+		last = g[1]
+	}
+
+	return spans, nil
+}
+
+// Option defines options for creating new regex redactors.
+type Option func(*RegexRedactor)
+
+/*
+WithHook sets a hook that is notified of the redactions performed by each
+pair, under the pair's name. Default is nil.
+*/
+func WithHook(hook metrics.Hook) Option {
+	return func(r *RegexRedactor) {
+		r.hook = hook
+	}
 }
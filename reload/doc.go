@@ -0,0 +1,4 @@
+// Package reload provides the ReloadableRedactor, a thread-safe redactor
+// whose underlying redactor can be swapped at runtime, and a Watcher that
+// rebuilds it whenever a configuration file changes.
+package reload
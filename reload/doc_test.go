@@ -0,0 +1,36 @@
+package reload
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kristinjeanna/redact/substring"
+)
+
+func ExampleReloadableRedactor() {
+	redactor, err := New(substring.New("password", "[redacted]"))
+	if err != nil {
+		log.Fatalf("an error occurred while creating redactor: %s", err)
+	}
+	sampleString := "the password is in the token"
+
+	result, err := redactor.Redact(sampleString)
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+	fmt.Println(result)
+
+	// swap the rules, e.g. after the configuration has changed
+	if _, err := redactor.Swap(substring.New("token", "[redacted]")); err != nil {
+		log.Fatalf("an error occurred while swapping redactors: %s", err)
+	}
+
+	result, err = redactor.Redact(sampleString)
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+	fmt.Println(result)
+	// Output:
+	// the [redacted] is in the token
+	// the password is in the [redacted]
+}
@@ -0,0 +1,65 @@
+package reload
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/kristinjeanna/redact"
+)
+
+var (
+	errRedactorNil = errors.New("reload.New: redactor must not be nil")
+	errSwapNil     = errors.New("reload.ReloadableRedactor.Swap: redactor must not be nil")
+)
+
+// ruleset holds the redactor currently in use by a ReloadableRedactor.
+type ruleset struct {
+	redactor redact.Redactor
+}
+
+// ReloadableRedactor is a redactor that delegates to an underlying redactor
+// which can be swapped at any time, including while Redact is being called
+// from other goroutines. Each call to Redact uses a single underlying
+// redactor from start to finish, either the one in use before a swap or the
+// one in use after it, and never takes a lock.
+type ReloadableRedactor struct {
+	current atomic.Pointer[ruleset]
+}
+
+// New returns a new ReloadableRedactor that initially delegates to the
+// specified redactor.
+func New(redactor redact.Redactor) (*ReloadableRedactor, error) {
+	if redactor == nil {
+		return nil, errRedactorNil
+	}
+
+	r := &ReloadableRedactor{}
+	r.current.Store(&ruleset{redactor: redactor})
+	return r, nil
+}
+
+// Redact redacts the input string with the current underlying redactor.
+func (r *ReloadableRedactor) Redact(s string) (string, error) {
+	return r.current.Load().redactor.Redact(s)
+}
+
+// Swap atomically replaces the underlying redactor and returns the previous
+// one. Calls to Redact already in progress complete with the previous one.
+func (r *ReloadableRedactor) Swap(redactor redact.Redactor) (redact.Redactor, error) {
+	if redactor == nil {
+		return nil, errSwapNil
+	}
+
+	return r.current.Swap(&ruleset{redactor: redactor}).redactor, nil
+}
+
+// Current returns the current underlying redactor.
+func (r *ReloadableRedactor) Current() redact.Redactor {
+	return r.current.Load().redactor
+}
+
+// String returns a text representation of the redactor.
+func (r *ReloadableRedactor) String() string {
+	return fmt.Sprintf("{current=%v}", r.Current())
+}
@@ -0,0 +1,132 @@
+package reload
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/chain"
+	"github.com/kristinjeanna/redact/simple"
+	"github.com/kristinjeanna/redact/substring"
+)
+
+func TestRedact(t *testing.T) {
+	r, err := New(substring.New("secret", "[redacted]"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	got, err := r.Redact("a secret")
+	if err != nil {
+		t.Error(err)
+	}
+	if got != "a [redacted]" {
+		t.Errorf("Expected '%s', but got '%s'", "a [redacted]", got)
+	}
+
+	old, err := r.Swap(substring.New("secret", "XXXXX"))
+	if err != nil {
+		t.Error(err)
+	}
+	if fmt.Sprint(old) != `{substring="secret"; replacement="[redacted]"}` {
+		t.Errorf("Swap returned an unexpected redactor: %v", old)
+	}
+
+	got, err = r.Redact("a secret")
+	if err != nil {
+		t.Error(err)
+	}
+	if got != "a XXXXX" {
+		t.Errorf("Expected '%s', but got '%s'", "a XXXXX", got)
+	}
+}
+
+func TestNew_err(t *testing.T) {
+	_, err := New(nil)
+	if err != errRedactorNil {
+		t.Errorf("Expected '%v', but got '%v'", errRedactorNil, err)
+	}
+
+	r, err := New(simple.New("X"))
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = r.Swap(nil)
+	if err != errSwapNil {
+		t.Errorf("Expected '%v', but got '%v'", errSwapNil, err)
+	}
+}
+
+// TestSwap_concurrent verifies, under the race detector, that concurrent
+// Redact calls observe either the old or the new ruleset, never a mix.
+func TestSwap_concurrent(t *testing.T) {
+	ruleset := func(repl string) redact.Redactor {
+		return chain.New([]redact.Redactor{
+			substring.New("a", repl),
+			substring.New("b", repl),
+			substring.New("c", repl),
+		})
+	}
+	rulesets := []redact.Redactor{ruleset("1"), ruleset("2")}
+
+	r, err := New(rulesets[0])
+	if err != nil {
+		t.Error(err)
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	errs := make(chan error, 8)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				got, err := r.Redact("abc")
+				if err != nil {
+					errs <- err
+					return
+				}
+				if got != "111" && got != "222" {
+					errs <- fmt.Errorf("observed a mix of rulesets: %q", got)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 1000; i++ {
+		if _, err := r.Swap(rulesets[i%2]); err != nil {
+			t.Error(err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestString(t *testing.T) {
+	r, err := New(simple.New("X"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := `{current={replacement="X"}}`
+	got := r.String()
+
+	if expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
@@ -0,0 +1,167 @@
+package reload
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kristinjeanna/redact"
+)
+
+const (
+	defaultInterval = 5 * time.Second
+)
+
+var (
+	errTargetNil    = errors.New("reload.Watch: target redactor must not be nil")
+	errBuildFuncNil = errors.New("reload.Watch: build function must not be nil")
+
+	errMsgFmtStatFailure  = "reload.Watch: unable to stat file, %w"
+	errMsgFmtBuildFailure = "reload.Watch: unable to build redactor from file, %w"
+)
+
+// BuildFunc builds a redactor from the configuration file at path.
+type BuildFunc func(path string) (redact.Redactor, error)
+
+// Watcher polls a configuration file and, whenever its modification time or
+// size changes, rebuilds the underlying redactor of a ReloadableRedactor
+// from it. If rebuilding fails, the previous redactor remains in use.
+type Watcher struct {
+	target   *ReloadableRedactor
+	path     string
+	build    BuildFunc
+	interval time.Duration
+	onError  func(error)
+
+	modTime time.Time
+	size    int64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Watch builds a redactor from the configuration file at path, swaps it
+// into the target and starts polling the file for changes in a background
+// goroutine. It returns an error, and doesn't start polling, if the initial
+// build fails. Call Stop to stop polling.
+func Watch(target *ReloadableRedactor, path string, build BuildFunc, opts ...Option) (*Watcher, error) {
+	if target == nil {
+		return nil, errTargetNil
+	}
+
+	if build == nil {
+		return nil, errBuildFuncNil
+	}
+
+	w := &Watcher{
+		target:   target,
+		path:     path,
+		build:    build,
+		interval: defaultInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(w)
+	}
+	if w.interval <= 0 {
+		w.interval = defaultInterval
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFmtStatFailure, err)
+	}
+	if err := w.reload(info); err != nil {
+		return nil, err
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Stop stops polling the file and waits for the background goroutine to
+// exit. It is safe to call Stop more than once.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+// run polls the file until the watcher is stopped.
+func (w *Watcher) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if err := w.poll(); err != nil && w.onError != nil {
+				w.onError(err)
+			}
+		}
+	}
+}
+
+// poll rebuilds the redactor if the file has changed since the last build.
+func (w *Watcher) poll() error {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return fmt.Errorf(errMsgFmtStatFailure, err)
+	}
+
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return nil
+	}
+
+	return w.reload(info)
+}
+
+// reload builds a redactor from the file and swaps it into the target.
+func (w *Watcher) reload(info os.FileInfo) error {
+	// record the file state first so that a broken file is not rebuilt on
+	// every poll, only after its next change
+	w.modTime = info.ModTime()
+	w.size = info.Size()
+
+	redactor, err := w.build(w.path)
+	if err != nil {
+		return fmt.Errorf(errMsgFmtBuildFailure, err)
+	}
+	if _, err := w.target.Swap(redactor); err != nil {
+		return fmt.Errorf(errMsgFmtBuildFailure, err)
+	}
+
+	return nil
+}
+
+// Option defines options for creating new watchers.
+type Option func(*Watcher)
+
+/*
+WithInterval sets how often the file is polled for changes. Default is 5
+seconds.
+*/
+func WithInterval(interval time.Duration) Option {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+/*
+WithErrorHandler sets a function that is called with the error whenever
+polling or rebuilding fails. Default is nil, which ignores such errors.
+*/
+func WithErrorHandler(onError func(error)) Option {
+	return func(w *Watcher) {
+		w.onError = onError
+	}
+}
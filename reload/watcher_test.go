@@ -0,0 +1,121 @@
+package reload
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/simple"
+	"github.com/kristinjeanna/redact/substring"
+)
+
+// buildFromFile builds a substring redactor for the trimmed file contents.
+func buildFromFile(path string) (redact.Redactor, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	word := strings.TrimSpace(string(b))
+	if word == "" {
+		return nil, errors.New("empty rules file")
+	}
+	return substring.New(word, "[redacted]"), nil
+}
+
+func writeRules(t *testing.T, path string, contents string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitFor(t *testing.T, r redact.Redactor, input string, expected string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := r.Redact(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got == expected {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected '%s', but got '%s'", expected, got)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.txt")
+	start := time.Now().Add(-time.Hour)
+	writeRules(t, path, "password", start)
+
+	r, err := New(simple.New("X"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 16)
+	w, err := Watch(r, path, buildFromFile,
+		WithInterval(10*time.Millisecond),
+		WithErrorHandler(func(err error) { errs <- err }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	waitFor(t, r, "password token", "[redacted] token")
+
+	writeRules(t, path, "token", start.Add(time.Minute))
+	waitFor(t, r, "password token", "password [redacted]")
+
+	// a broken file keeps the previous rules in place
+	writeRules(t, path, "", start.Add(2*time.Minute))
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("Expected an error, but got nil")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the error handler to be called")
+	}
+	waitFor(t, r, "password token", "password [redacted]")
+
+	w.Stop()
+	w.Stop() // safe to call twice
+}
+
+func TestWatch_err(t *testing.T) {
+	r, err := New(simple.New("X"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Watch(nil, "rules.txt", buildFromFile); err != errTargetNil {
+		t.Errorf("Expected '%v', but got '%v'", errTargetNil, err)
+	}
+
+	if _, err := Watch(r, "rules.txt", nil); err != errBuildFuncNil {
+		t.Errorf("Expected '%v', but got '%v'", errBuildFuncNil, err)
+	}
+
+	if _, err := Watch(r, filepath.Join(t.TempDir(), "missing.txt"), buildFromFile); err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+
+	path := filepath.Join(t.TempDir(), "rules.txt")
+	writeRules(t, path, "", time.Now())
+	if _, err := Watch(r, path, buildFromFile); err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+}
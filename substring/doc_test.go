@@ -17,3 +17,23 @@ func ExampleSubstringRedactor() {
 	fmt.Println(result)
 	// Output: this string XXXXX information
 }
+
+func ExampleNewFromOptions() {
+	redactor, err := NewFromOptions("password",
+		WithIgnoreCase(true),
+		WithWholeWord(true),
+		WithReplacementText("XXXXX"),
+	)
+	if err != nil {
+		log.Fatalf("an error occurred while creating redactor: %s", err)
+	}
+	sampleString := "Password: hunter2; passwords: PASSWORD"
+
+	result, err := redactor.Redact(sampleString)
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+
+	fmt.Println(result)
+	// Output: XXXXX: hunter2; passwords: XXXXX
+}
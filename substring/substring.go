@@ -1,17 +1,30 @@
 package substring
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/kristinjeanna/redact"
 )
 
+var (
+	errSubstringEmpty = errors.New("substring.NewFromOptions: substring is required")
+
+	errMsgFmtRedactFailure = "substring.SubstringRedactor.Redact: error while redacting, %w"
+)
+
 // SubstringRedactor is a redactor that replaces all occurrences of
 // the substring in the specified string.
 type SubstringRedactor struct {
-	substring   string
-	replacement string
+	substring       string
+	replacement     string
+	ignoreCase      bool
+	wholeWord       bool
+	maxReplacements uint
+	redactor        redact.Redactor
 }
 
 // New returns a new SubstringRedactor.
@@ -22,12 +35,240 @@ func New(substring string, replacement string) redact.Redactor {
 	}
 }
 
+// NewFromOptions creates a new SubstringRedactor for the substring with the
+// provided options. Without options, the redactor behaves like one created
+// via New with an empty replacement string.
+func NewFromOptions(substring string, opts ...Option) (redact.Redactor, error) {
+	if len(substring) == 0 {
+		return nil, errSubstringEmpty
+	}
+
+	r := SubstringRedactor{substring: substring}
+	for _, o := range opts {
+		o(&r)
+	}
+
+	return r, nil
+}
+
 // Redact replaces all occurrences of the substring in the specified string.
 func (r SubstringRedactor) Redact(s string) (string, error) {
-	return strings.ReplaceAll(s, r.substring, r.replacement), nil
+	if !r.ignoreCase && !r.wholeWord && r.maxReplacements == 0 && r.redactor == nil {
+		return strings.ReplaceAll(s, r.substring, r.replacement), nil
+	}
+
+	spans, err := r.Detect(s)
+	if err != nil {
+		return "", err
+	}
+	return redact.ApplySpans(s, spans), nil
+}
+
+// Detect returns the spans of the occurrences of the substring in s. An
+// empty substring never yields a span.
+func (r SubstringRedactor) Detect(s string) ([]redact.Span, error) {
+	if len(r.substring) == 0 {
+		return nil, nil
+	}
+
+	var spans []redact.Span
+	for pos := 0; r.maxReplacements == 0 || uint(len(spans)) < r.maxReplacements; {
+		start, end := r.find(s, pos)
+		if start < 0 {
+			break
+		}
+
+		repl := r.replacement
+		if r.redactor != nil {
+			var err error
+			repl, err = r.redactor.Redact(s[start:end])
+			if err != nil {
+				return nil, fmt.Errorf(errMsgFmtRedactFailure, err)
+			}
+		}
+
+		spans = append(spans, redact.Span{Start: start, End: end, Replacement: repl})
+		pos = end
+	}
+
+	return spans, nil
 }
 
 // String returns a text representation of the redactor.
 func (r SubstringRedactor) String() string {
-	return fmt.Sprintf("{substring=%q; replacement=%q}", r.substring, r.replacement)
+	var b strings.Builder
+	fmt.Fprintf(&b, "{substring=%q; replacement=%q", r.substring, r.replacement)
+	if r.ignoreCase {
+		b.WriteString("; ignoreCase=true")
+	}
+	if r.wholeWord {
+		b.WriteString("; wholeWord=true")
+	}
+	if r.maxReplacements != 0 {
+		fmt.Fprintf(&b, "; maxReplacements=%d", r.maxReplacements)
+	}
+	if r.redactor != nil {
+		fmt.Fprintf(&b, "; redactor=%v", r.redactor)
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// find returns the byte offsets of the first occurrence of the substring in
+// s at or after the byte offset from, honoring the case and word boundary
+// settings of the redactor. It returns -1, -1 if there is no occurrence.
+func (r SubstringRedactor) find(s string, from int) (int, int) {
+	for from <= len(s) {
+		var start, end int
+		if r.ignoreCase {
+			start, end = indexFold(s, from, r.substring)
+		} else {
+			idx := strings.Index(s[from:], r.substring)
+			if idx < 0 {
+				return -1, -1
+			}
+			start, end = from+idx, from+idx+len(r.substring)
+		}
+		if start < 0 {
+			return -1, -1
+		}
+
+		if !r.wholeWord || isWordBoundary(s, start, end) {
+			return start, end
+		}
+
+		// skip the rune at start and keep looking
+		_, size := utf8.DecodeRuneInString(s[start:])
+		from = start + size
+	}
+
+	return -1, -1
+}
+
+// indexFold returns the byte offsets of the first occurrence of substr in s
+// at or after the byte offset from under Unicode simple case folding.
+func indexFold(s string, from int, substr string) (int, int) {
+	for i := from; i < len(s); {
+		if end, ok := hasPrefixFold(s[i:], substr); ok {
+			return i, i + end
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+
+	return -1, -1
+}
+
+// hasPrefixFold reports whether s begins with prefix under Unicode simple
+// case folding. If so, it also returns the byte length of the prefix in s,
+// which may differ from len(prefix).
+func hasPrefixFold(s, prefix string) (int, bool) {
+	i := 0
+	for _, pr := range prefix {
+		if i >= len(s) {
+			return 0, false
+		}
+		sr, size := utf8.DecodeRuneInString(s[i:])
+		if !equalFoldRune(sr, pr) {
+			return 0, false
+		}
+		i += size
+	}
+
+	return i, true
+}
+
+// equalFoldRune reports whether the runes are equal under Unicode simple
+// case folding.
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isWordBoundary reports whether the text between the byte offsets start and
+// end of s is neither preceded nor followed by a word character.
+func isWordBoundary(s string, start, end int) bool {
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		if isWordRune(before) {
+			return false
+		}
+	}
+	if end < len(s) {
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if isWordRune(after) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isWordRune reports whether the rune is a letter, a digit or an underscore.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Option defines options for creating new substring redactors.
+type Option func(*SubstringRedactor)
+
+/*
+WithReplacementText sets the text that replaces each occurrence of the
+substring. Default is "" (the empty string).
+*/
+func WithReplacementText(replacement string) Option {
+	return func(r *SubstringRedactor) {
+		r.replacement = replacement
+	}
+}
+
+/*
+WithIgnoreCase enables matching the substring under Unicode case folding,
+so that "password" also matches "Password" and "PASSWORD". Default is false.
+*/
+func WithIgnoreCase(ignoreCase bool) Option {
+	return func(r *SubstringRedactor) {
+		r.ignoreCase = ignoreCase
+	}
+}
+
+/*
+WithWholeWord restricts matches to occurrences of the substring that are
+neither preceded nor followed by a letter, digit or underscore. Default is
+false.
+*/
+func WithWholeWord(wholeWord bool) Option {
+	return func(r *SubstringRedactor) {
+		r.wholeWord = wholeWord
+	}
+}
+
+/*
+WithMaxReplacements limits redaction to the first n occurrences of the
+substring. Default is 0, which replaces all occurrences.
+*/
+func WithMaxReplacements(n uint) Option {
+	return func(r *SubstringRedactor) {
+		r.maxReplacements = n
+	}
+}
+
+/*
+WithRedactor sets a redactor that is invoked on each occurrence of the
+substring to produce its replacement, in place of the replacement text.
+Default is nil, which uses the replacement text.
+*/
+func WithRedactor(redactor redact.Redactor) Option {
+	return func(r *SubstringRedactor) {
+		r.redactor = redactor
+	}
 }
@@ -3,6 +3,9 @@ package substring
 import (
 	"fmt"
 	"testing"
+
+	"github.com/kristinjeanna/redact/middle"
+	"github.com/kristinjeanna/redact/url"
 )
 
 type testCase struct {
@@ -44,3 +47,87 @@ func TestString(t *testing.T) {
 		t.Errorf("Expected '%s', but got '%s'", expected, got)
 	}
 }
+
+func TestNewFromOptions(t *testing.T) {
+	type testCase struct {
+		input    string   // string to be redacted
+		opts     []Option // redactor options
+		expected string   // expected output
+	}
+
+	m, err := middle.NewFromOptions(middle.WithReplacementText("***"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	cases := []testCase{
+		{"password Password PASSWORD", []Option{WithReplacementText("X")}, "X Password PASSWORD"},
+		{"password Password PASSWORD", []Option{WithReplacementText("X"), WithIgnoreCase(true)}, "X X X"},
+		{"passwords password _password", []Option{WithReplacementText("X"), WithWholeWord(true)},
+			"passwords X _password"},
+		{"password-password", []Option{WithReplacementText("X"), WithWholeWord(true)}, "X-X"},
+		{"password password password", []Option{WithReplacementText("X"), WithMaxReplacements(2)},
+			"X X password"},
+		{"PASSWORDS Password password", []Option{WithReplacementText("X"), WithIgnoreCase(true),
+			WithWholeWord(true), WithMaxReplacements(1)}, "PASSWORDS X password"},
+		{"[PAſſWORD]", []Option{WithReplacementText("X"), WithIgnoreCase(true)}, "[X]"},
+		{"pass: password_1 and PASSWORD_2", []Option{WithRedactor(m), WithIgnoreCase(true)},
+			"pass: pas***_1 and PAS***_2"},
+		{"no match here", []Option{WithReplacementText("X"), WithIgnoreCase(true)}, "no match here"},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("input=%q;expected=%q; ", tc.input, tc.expected), func(t *testing.T) {
+			r, err := NewFromOptions("password", tc.opts...)
+			if err != nil {
+				t.Error(err)
+			}
+			got, err := r.Redact(tc.input)
+			if err != nil {
+				t.Error(err)
+			}
+			if tc.expected != got {
+				t.Errorf("Expected '%s', but got '%s'", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewFromOptions_errSubstringEmpty(t *testing.T) {
+	_, err := NewFromOptions("")
+	if err != errSubstringEmpty {
+		t.Errorf("Expected '%v', but got '%v'", errSubstringEmpty, err)
+	}
+}
+
+func TestRedact_errorsRedactFail(t *testing.T) {
+	r, err := NewFromOptions("string", WithRedactor(url.New("foo", nil)))
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = r.Redact("this is a string")
+	if err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+}
+
+func TestString_withOptions(t *testing.T) {
+	redactor, err := NewFromOptions("foo",
+		WithReplacementText("bar"),
+		WithIgnoreCase(true),
+		WithWholeWord(true),
+		WithMaxReplacements(2),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	stringer := redactor.(fmt.Stringer)
+
+	expected := `{substring="foo"; replacement="bar"; ignoreCase=true; wholeWord=true; maxReplacements=2}`
+	got := stringer.String()
+
+	if expected != got {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}
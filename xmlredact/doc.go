@@ -0,0 +1,6 @@
+// Package xmlredact provides a redactor for XML and HTML documents that
+// redacts the text content of selected elements and the values of selected
+// attributes. Documents are streamed through the encoding/xml tokenizer,
+// and every token left unredacted is copied byte for byte, so comments,
+// CDATA sections, whitespace and formatting are preserved.
+package xmlredact
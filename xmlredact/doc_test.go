@@ -0,0 +1,23 @@
+package xmlredact
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kristinjeanna/redact/simple"
+)
+
+func ExampleNew() {
+	redactor, err := New(simple.New("[redacted]"), "Login/Password", "@token")
+	if err != nil {
+		log.Fatalf("an error occurred while creating redactor: %s", err)
+	}
+
+	result, err := redactor.Redact(`<Login token="abc123"><User>bob</User><Password>s3cret</Password></Login>`)
+	if err != nil {
+		log.Fatalf("an error occurred while redacting: %s", err)
+	}
+
+	fmt.Println(result)
+	// Output: <Login token="[redacted]"><User>bob</User><Password>[redacted]</Password></Login>
+}
@@ -0,0 +1,115 @@
+package xmlredact
+
+import (
+	"bufio"
+)
+
+// recorder is an io.ByteReader recording the bytes read, so that the raw
+// bytes of each token read by an xml.Decoder can be retrieved via take.
+type recorder struct {
+	r      *bufio.Reader
+	buf    []byte
+	offset int64 // input offset of buf[0]
+}
+
+func (r *recorder) ReadByte() (byte, error) {
+	c, err := r.r.ReadByte()
+	if err == nil {
+		r.buf = append(r.buf, c)
+	}
+	return c, err
+}
+
+func (r *recorder) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.buf = append(r.buf, p[:n]...)
+	return n, err
+}
+
+// take returns the recorded bytes up to the input offset end and discards
+// them from the recording.
+func (r *recorder) take(end int64) []byte {
+	n := int(end - r.offset)
+	raw := make([]byte, n)
+	copy(raw, r.buf[:n])
+
+	r.buf = r.buf[:copy(r.buf, r.buf[n:])]
+	r.offset = end
+
+	return raw
+}
+
+// attrValue holds the byte offsets of an attribute value in a raw start tag,
+// excluding its quotes, and the quote character, if any. Offsets are -1 for
+// an attribute without value.
+type attrValue struct {
+	start, end int
+	quote      byte
+}
+
+// scanAttrValues returns the locations of the values of the attributes in
+// the raw start tag, in order.
+func scanAttrValues(raw []byte) []attrValue {
+	var values []attrValue
+
+	i := 1 // skip '<'
+	for i < len(raw) && !isSpace(raw[i]) && raw[i] != '>' && raw[i] != '/' {
+		i++ // element name
+	}
+
+	for {
+		for i < len(raw) && isSpace(raw[i]) {
+			i++
+		}
+		if i >= len(raw) || raw[i] == '>' || raw[i] == '/' {
+			return values
+		}
+
+		for i < len(raw) && !isSpace(raw[i]) && raw[i] != '=' && raw[i] != '>' && raw[i] != '/' {
+			i++ // attribute name
+		}
+
+		j := i
+		for j < len(raw) && isSpace(raw[j]) {
+			j++
+		}
+		if j >= len(raw) || raw[j] != '=' {
+			values = append(values, attrValue{start: -1, end: -1})
+			continue
+		}
+		i = j + 1
+		for i < len(raw) && isSpace(raw[i]) {
+			i++
+		}
+
+		v := attrValue{start: i}
+		if i < len(raw) && (raw[i] == '"' || raw[i] == '\'') {
+			v.quote = raw[i]
+			v.start++
+			i++
+			for i < len(raw) && raw[i] != v.quote {
+				i++
+			}
+			v.end = i
+			i++
+		} else {
+			// unquoted values are only accepted by non-strict decoders
+			for i < len(raw) && isNameByte(raw[i]) {
+				i++
+			}
+			v.end = i
+		}
+		values = append(values, v)
+	}
+}
+
+// isNameByte reports whether c may appear in an unquoted attribute value,
+// following the rules of the non-strict xml.Decoder.
+func isNameByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '_' || c == ':' || c == '-'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
@@ -0,0 +1,182 @@
+package xmlredact
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const errMsgFmtBadSelector = "xmlredact.NewFromOptions: invalid selector %q"
+
+// step matches an element or attribute name. An empty space matches any
+// namespace, and a local name of "*" any local name.
+type step struct {
+	space string
+	local string
+}
+
+func (s step) matches(name xml.Name) bool {
+	return (s.space == "" || s.space == name.Space) && (s.local == "*" || s.local == name.Local)
+}
+
+/*
+selector selects elements, or attributes of elements, by path. The syntax is
+a sequence of steps separated by "/", where each step is a local name, "*",
+or either of these preceded by a namespace in braces, as in
+"{urn:example}Password". A final step starting with "@" selects attributes.
+A selector starting with "/" matches paths from the root element; otherwise
+it matches paths ending at any depth. Examples:
+
+	Password
+	/Envelope/Body/Login/Password
+	{urn:oasis:names:tc:SAML:2.0:assertion}Assertion/*
+	@token
+	Login/@{urn:example}key
+*/
+type selector struct {
+	anchored bool
+	steps    []step
+	attr     *step
+}
+
+// parseSelector parses the selector text s.
+func parseSelector(s string) (selector, error) {
+	var sel selector
+	badSelector := fmt.Errorf(errMsgFmtBadSelector, s)
+
+	rest := s
+	if strings.HasPrefix(rest, "/") {
+		sel.anchored = true
+		rest = rest[1:]
+	}
+
+	parts, ok := splitSteps(rest)
+	if !ok {
+		return sel, badSelector
+	}
+
+	for i, part := range parts {
+		isAttr := strings.HasPrefix(part, "@")
+		if isAttr {
+			if i != len(parts)-1 {
+				return sel, badSelector
+			}
+			part = part[1:]
+		}
+
+		st, ok := parseStep(part)
+		if !ok {
+			return sel, badSelector
+		}
+
+		if isAttr {
+			sel.attr = &st
+		} else {
+			sel.steps = append(sel.steps, st)
+		}
+	}
+
+	if sel.anchored && len(sel.steps) == 0 {
+		return sel, badSelector
+	}
+
+	return sel, nil
+}
+
+// splitSteps splits s at the slashes outside braces.
+func splitSteps(s string) ([]string, bool) {
+	var parts []string
+	depth, start := 0, 0
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '/':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+		if depth < 0 || depth > 1 {
+			return nil, false
+		}
+	}
+	parts = append(parts, s[start:])
+
+	return parts, depth == 0
+}
+
+// parseStep parses a step of the form "local" or "{space}local".
+func parseStep(s string) (step, bool) {
+	var st step
+	if strings.HasPrefix(s, "{") {
+		end := strings.IndexByte(s, '}')
+		st.space, s = s[1:end], s[end+1:]
+		if st.space == "" {
+			return st, false
+		}
+	}
+
+	if s == "" || strings.ContainsAny(s, "{}@ \t\r\n") {
+		return st, false
+	}
+	st.local = s
+
+	return st, true
+}
+
+// matchesElement reports whether the path of element names, from the root
+// to the current element, is selected.
+func (s selector) matchesElement(path []xml.Name) bool {
+	if len(s.steps) > len(path) || (s.anchored && len(s.steps) != len(path)) {
+		return false
+	}
+
+	offset := len(path) - len(s.steps)
+	for i, st := range s.steps {
+		if !st.matches(path[offset+i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAttr reports whether the attribute of the element at the end of
+// the path is selected.
+func (s selector) matchesAttr(path []xml.Name, attr xml.Name) bool {
+	return s.attr != nil && s.attr.matches(attr) && s.matchesElement(path)
+}
+
+// String returns the selector text.
+func (s selector) String() string {
+	var b strings.Builder
+	if s.anchored {
+		b.WriteString("/")
+	}
+
+	for i, st := range s.steps {
+		if i > 0 {
+			b.WriteString("/")
+		}
+		b.WriteString(st.String())
+	}
+
+	if s.attr != nil {
+		if len(s.steps) > 0 {
+			b.WriteString("/")
+		}
+		b.WriteString("@" + s.attr.String())
+	}
+
+	return b.String()
+}
+
+func (s step) String() string {
+	if s.space != "" {
+		return "{" + s.space + "}" + s.local
+	}
+	return s.local
+}
@@ -0,0 +1,341 @@
+package xmlredact
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kristinjeanna/redact"
+)
+
+const cdataStart, cdataEnd = "<![CDATA[", "]]>"
+
+var (
+	errNoRules     = errors.New("xmlredact.NewFromOptions: at least one rule is required")
+	errRedactorNil = errors.New("xmlredact.NewFromOptions: redactor must not be nil")
+	errAttrScan    = errors.New("xmlredact.XMLRedactor.RedactStream: unable to locate attributes in start tag")
+
+	errMsgFmtRedactFailure = "xmlredact.XMLRedactor.RedactStream: error while redacting %s, %w"
+)
+
+// rule associates a selector with the redactor applied to the selected
+// content.
+type rule struct {
+	selector selector
+	redactor redact.Redactor
+}
+
+// XMLRedactor is a redactor for XML and HTML documents. Each rule selects
+// elements, whose text content is redacted along with that of their
+// descendants, or attributes, whose values are redacted. When several rules
+// select the same content, the first one applies, except that the text of
+// an element is redacted by the rule selecting its innermost selected
+// ancestor. Text consisting of whitespace only is left intact, and the
+// leading and trailing whitespace of other text is preserved.
+type XMLRedactor struct {
+	rules []rule
+	html  bool
+}
+
+// New returns a new XMLRedactor for XML documents that applies the
+// redactor to the content selected by each of the selectors. See
+// WithRule for the selector syntax. To stream documents via RedactStream,
+// assert the returned redactor to an XMLRedactor.
+func New(redactor redact.Redactor, selectors ...string) (redact.Redactor, error) {
+	opts := make([]Option, len(selectors))
+	for i, s := range selectors {
+		opts[i] = WithRule(s, redactor)
+	}
+
+	return NewFromOptions(opts...)
+}
+
+// NewFromOptions returns a new XMLRedactor with the provided options.
+func NewFromOptions(opts ...Option) (redact.Redactor, error) {
+	var b builder
+	for _, o := range opts {
+		o(&b)
+	}
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if len(b.r.rules) == 0 {
+		return nil, errNoRules
+	}
+
+	return b.r, nil
+}
+
+// Redact redacts the document in s.
+func (r XMLRedactor) Redact(s string) (string, error) {
+	var b strings.Builder
+	if err := r.RedactStream(strings.NewReader(s), &b); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// RedactStream reads a document from src and writes it to dst with the
+// selected content redacted.
+func (r XMLRedactor) RedactStream(src io.Reader, dst io.Writer) error {
+	rec := &recorder{r: bufio.NewReader(src)}
+	d := xml.NewDecoder(rec)
+	if r.html {
+		d.Strict = false
+		d.AutoClose = xml.HTMLAutoClose
+		d.Entity = xml.HTMLEntity
+	}
+
+	w := bufio.NewWriter(dst)
+
+	type frame struct {
+		text redact.Redactor // redactor for the text content, if selected
+	}
+	var path []xml.Name
+	var frames []frame
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		raw := rec.take(d.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			path = append(path, t.Name)
+
+			var f frame
+			if len(frames) > 0 {
+				f = frames[len(frames)-1]
+			}
+			if redactor := r.elementRedactor(path); redactor != nil {
+				f.text = redactor
+			}
+			frames = append(frames, f)
+
+			if raw, err = r.redactAttrs(raw, path, t.Attr); err != nil {
+				return err
+			}
+
+		case xml.EndElement:
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+				frames = frames[:len(frames)-1]
+			}
+
+		case xml.CharData:
+			if len(frames) > 0 && frames[len(frames)-1].text != nil {
+				if raw, err = redactText(raw, string(t), frames[len(frames)-1].text, path); err != nil {
+					return err
+				}
+			}
+		}
+
+		if _, err := w.Write(raw); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// String returns a text representation of the redactor.
+func (r XMLRedactor) String() string {
+	rules := make([]string, len(r.rules))
+	for i, rl := range r.rules {
+		rules[i] = fmt.Sprintf("%q: %v", rl.selector, rl.redactor)
+	}
+
+	return fmt.Sprintf("{html=%t; rules=[%s]}", r.html, strings.Join(rules, ", "))
+}
+
+// elementRedactor returns the redactor of the first rule selecting the
+// element at the end of the path, or nil.
+func (r XMLRedactor) elementRedactor(path []xml.Name) redact.Redactor {
+	for _, rl := range r.rules {
+		if rl.selector.attr == nil && rl.selector.matchesElement(path) {
+			return rl.redactor
+		}
+	}
+	return nil
+}
+
+// attrRedactor returns the redactor of the first rule selecting the
+// attribute of the element at the end of the path, or nil.
+func (r XMLRedactor) attrRedactor(path []xml.Name, attr xml.Name) redact.Redactor {
+	if attr.Space == "xmlns" || (attr.Space == "" && attr.Local == "xmlns") {
+		return nil // namespace declaration
+	}
+
+	for _, rl := range r.rules {
+		if rl.selector.matchesAttr(path, attr) {
+			return rl.redactor
+		}
+	}
+	return nil
+}
+
+// redactAttrs returns the raw start tag with the values of the selected
+// attributes redacted.
+func (r XMLRedactor) redactAttrs(raw []byte, path []xml.Name, attrs []xml.Attr) ([]byte, error) {
+	var redactors []redact.Redactor
+	for i, a := range attrs {
+		if redactor := r.attrRedactor(path, a.Name); redactor != nil {
+			if redactors == nil {
+				redactors = make([]redact.Redactor, len(attrs))
+			}
+			redactors[i] = redactor
+		}
+	}
+
+	if redactors == nil {
+		return raw, nil
+	}
+
+	values := scanAttrValues(raw)
+	if len(values) != len(attrs) {
+		return nil, errAttrScan
+	}
+
+	var b bytes.Buffer
+	last := 0
+	for i, v := range values {
+		if redactors[i] == nil || v.start < 0 {
+			continue
+		}
+
+		out, err := redactors[i].Redact(attrs[i].Value)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFmtRedactFailure, pathString(path)+"/@"+attrs[i].Name.Local, err)
+		}
+
+		b.Write(raw[last:v.start])
+		if v.quote == 0 {
+			// quote a redacted unquoted HTML attribute value
+			b.WriteString(`"` + escape(out, '"') + `"`)
+		} else {
+			b.WriteString(escape(out, v.quote))
+		}
+		last = v.end
+	}
+	b.Write(raw[last:])
+
+	return b.Bytes(), nil
+}
+
+// redactText returns the raw text or CDATA section with the text redacted,
+// keeping its leading and trailing whitespace.
+func redactText(raw []byte, text string, redactor redact.Redactor, path []xml.Name) ([]byte, error) {
+	core := strings.TrimSpace(text)
+	if core == "" {
+		return raw, nil
+	}
+	lead := text[:strings.Index(text, core)]
+	trail := text[len(lead)+len(core):]
+
+	out, err := redactor.Redact(core)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFmtRedactFailure, pathString(path), err)
+	}
+
+	if bytes.HasPrefix(raw, []byte(cdataStart)) {
+		out = strings.ReplaceAll(out, cdataEnd, "]]"+cdataEnd+cdataStart+">")
+		return []byte(cdataStart + lead + out + trail + cdataEnd), nil
+	}
+
+	return []byte(escape(lead, 0) + escape(out, 0) + escape(trail, 0)), nil
+}
+
+// escape escapes the characters of s that cannot appear literally in text
+// or, if quote is not 0, in an attribute value enclosed in quote.
+func escape(s string, quote byte) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '&':
+			b.WriteString("&amp;")
+		case c == '<':
+			b.WriteString("&lt;")
+		case c == '>':
+			b.WriteString("&gt;")
+		case c == '"' && quote == '"':
+			b.WriteString("&quot;")
+		case c == '\'' && quote == '\'':
+			b.WriteString("&apos;")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// pathString returns the path of element names separated by slashes.
+func pathString(path []xml.Name) string {
+	var b strings.Builder
+	for _, n := range path {
+		b.WriteString("/" + n.Local)
+	}
+	return b.String()
+}
+
+// Option defines options for creating new XML redactors.
+type Option func(*builder)
+
+// builder collects the settings and the first error of the options.
+type builder struct {
+	r   XMLRedactor
+	err error
+}
+
+/*
+WithRule adds a rule applying the redactor to the content selected by the
+selector. A selector is a sequence of steps separated by "/", where each
+step is a local name, "*", or either of these preceded by a namespace in
+braces, as in "{urn:example}Password". A final step starting with "@"
+selects attributes rather than elements. A selector starting with "/"
+matches paths from the root element; otherwise it matches paths ending at
+any depth. For example, "Password" selects all Password elements, "@token"
+all token attributes, and "/Envelope/Body/Login/@key" only the key attribute
+of the Login element at that path. Default is no rules.
+*/
+func WithRule(selector string, redactor redact.Redactor) Option {
+	return func(b *builder) {
+		if b.err != nil {
+			return
+		}
+
+		if redactor == nil {
+			b.err = errRedactorNil
+			return
+		}
+
+		sel, err := parseSelector(selector)
+		if err != nil {
+			b.err = err
+			return
+		}
+
+		b.r.rules = append(b.r.rules, rule{selector: sel, redactor: redactor})
+	}
+}
+
+/*
+WithHTML sets whether documents are parsed as HTML, tolerating unclosed
+elements, unquoted attributes and HTML entities. Default is false.
+*/
+func WithHTML(html bool) Option {
+	return func(b *builder) {
+		b.r.html = html
+	}
+}
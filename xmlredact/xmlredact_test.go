@@ -0,0 +1,229 @@
+package xmlredact
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kristinjeanna/redact"
+	"github.com/kristinjeanna/redact/internal/redacttest"
+	"github.com/kristinjeanna/redact/simple"
+)
+
+const replacement = "[redacted]"
+
+const soap = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- login request -->
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:ex="urn:example">
+  <soap:Body>
+    <ex:Login token="abc&amp;123" ex:key='k1'>
+      <ex:User>bob</ex:User>
+      <ex:Password>
+        s3cr&lt;et
+      </ex:Password>
+      <ex:Note><![CDATA[password is <hidden>]]></ex:Note>
+    </ex:Login>
+  </soap:Body>
+</soap:Envelope>
+`
+
+func TestRedact(t *testing.T) {
+	var tests = []struct {
+		selectors []string
+		expected  string
+	}{
+		{[]string{"Nothing"}, soap},
+		{[]string{"Password"}, strings.Replace(soap, "s3cr&lt;et", replacement, 1)},
+		{[]string{"/Envelope/Body/Login/Password"}, strings.Replace(soap, "s3cr&lt;et", replacement, 1)},
+		{[]string{"/Body/Login/Password"}, soap},
+		{[]string{"{urn:example}Password"}, strings.Replace(soap, "s3cr&lt;et", replacement, 1)},
+		{[]string{"{urn:other}Password"}, soap},
+		{[]string{"Note"}, strings.Replace(soap, "password is <hidden>", replacement, 1)},
+		{[]string{"@token"}, strings.Replace(soap, "abc&amp;123", replacement, 1)},
+		{[]string{"Login/@{urn:example}key"}, strings.Replace(soap, "'k1'", "'"+replacement+"'", 1)},
+		{[]string{"User/@token"}, soap},
+		{[]string{"@*"}, strings.Replace(strings.Replace(soap, "abc&amp;123", replacement, 1), "'k1'", "'"+replacement+"'", 1)},
+		{
+			[]string{"Login"},
+			strings.NewReplacer("bob", replacement, "s3cr&lt;et", replacement, "password is <hidden>", replacement).Replace(soap),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("selectors=%q", tt.selectors), func(t *testing.T) {
+			redactor, err := New(simple.New(replacement), tt.selectors...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			actual, err := redactor.Redact(soap)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_escaping(t *testing.T) {
+	var tests = []struct {
+		replacement string
+		input       string
+		expected    string
+	}{
+		{"a<b&c>", "<p>x</p>", "<p>a&lt;b&amp;c&gt;</p>"},
+		{`"quoted"`, `<p a="x"/>`, `<p a="&quot;quoted&quot;"/>`},
+		{`it's`, `<p a='x'/>`, `<p a='it&apos;s'/>`},
+		{"a]]>b", "<p><![CDATA[x]]></p>", "<p><![CDATA[a]]]]><![CDATA[>b]]></p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("replacement=%q", tt.replacement), func(t *testing.T) {
+			redactor, err := New(simple.New(tt.replacement), "p", "@a")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			actual, err := redactor.Redact(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected '%s', but got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_rulePrecedence(t *testing.T) {
+	redactor, err := NewFromOptions(
+		WithRule("Secret", simple.New("[secret]")),
+		WithRule("Config", simple.New("[config]")),
+		WithRule("@id", simple.New("[id1]")),
+		WithRule("@id", simple.New("[id2]")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := `<Config id="1"><Name>n</Name><Secret>s<Inner>i</Inner></Secret></Config>`
+	expected := `<Config id="[id1]"><Name>[config]</Name><Secret>[secret]<Inner>[secret]</Inner></Secret></Config>`
+
+	actual, err := redactor.Redact(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
+
+func TestRedact_html(t *testing.T) {
+	redactor, err := NewFromOptions(
+		WithHTML(true),
+		WithRule("@value", simple.New("[redacted value]")),
+		WithRule("td", simple.New(replacement)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := `<html><body><input type=password value=hunter2 disabled><br><table><tr><td>r&eacute;sum&eacute;</td></tr></table></body></html>`
+	expected := `<html><body><input type=password value="[redacted value]" disabled><br><table><tr><td>[redacted]</td></tr></table></body></html>`
+
+	actual, err := redactor.Redact(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, actual)
+	}
+}
+
+func TestRedactStream_large(t *testing.T) {
+	redactor, err := New(simple.New("x"), "v")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var input, expected strings.Builder
+	input.WriteString("<root>")
+	expected.WriteString("<root>")
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&input, "<item n=\"%d\"><v>secret %d</v></item>\n", i, i)
+		fmt.Fprintf(&expected, "<item n=\"%d\"><v>x</v></item>\n", i)
+	}
+	input.WriteString("</root>")
+	expected.WriteString("</root>")
+
+	var out strings.Builder
+	if err := redactor.(XMLRedactor).RedactStream(strings.NewReader(input.String()), &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.String() != expected.String() {
+		t.Error("Expected the redacted document to match")
+	}
+}
+
+func TestNewFromOptions_errors(t *testing.T) {
+	var tests = []struct {
+		opts     []Option
+		expected string
+	}{
+		{nil, errNoRules.Error()},
+		{[]Option{WithRule("a", nil)}, errRedactorNil.Error()},
+		{[]Option{WithRule("", simple.New(""))}, fmt.Sprintf(errMsgFmtBadSelector, "")},
+		{[]Option{WithRule("a//b", simple.New(""))}, fmt.Sprintf(errMsgFmtBadSelector, "a//b")},
+		{[]Option{WithRule("@a/b", simple.New(""))}, fmt.Sprintf(errMsgFmtBadSelector, "@a/b")},
+		{[]Option{WithRule("/@a", simple.New(""))}, fmt.Sprintf(errMsgFmtBadSelector, "/@a")},
+		{[]Option{WithRule("{urn:x", simple.New(""))}, fmt.Sprintf(errMsgFmtBadSelector, "{urn:x")},
+		{[]Option{WithRule("{}a", simple.New(""))}, fmt.Sprintf(errMsgFmtBadSelector, "{}a")},
+		{[]Option{WithRule("a{b}", simple.New(""))}, fmt.Sprintf(errMsgFmtBadSelector, "a{b}")},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("expected=%q", tt.expected), func(t *testing.T) {
+			_, err := NewFromOptions(tt.opts...)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Expected error '%s', but got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestRedact_errors(t *testing.T) {
+	var tests = []struct {
+		redactor redact.Redactor
+		input    string
+		expected string
+	}{
+		{redacttest.Failing{}, "<a><b>x</b></a>", "xmlredact.XMLRedactor.RedactStream: error while redacting /a/b, failed"},
+		{redacttest.Failing{}, `<a k="x"/>`, "xmlredact.XMLRedactor.RedactStream: error while redacting /a/@k, failed"},
+		{simple.New(""), "<a><b>x</a>", "XML syntax error on line 1: element <b> closed by </a>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input=%q", tt.input), func(t *testing.T) {
+			redactor, err := New(tt.redactor, "b", "@k")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = redactor.Redact(tt.input)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Expected error '%s', but got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	redactor, err := New(simple.New(replacement), "/Envelope/{urn:x}Body/*", "Login/@{urn:x}key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{html=false; rules=["/Envelope/{urn:x}Body/*": {replacement="[redacted]"}, "Login/@{urn:x}key": {replacement="[redacted]"}]}`
+	if got := fmt.Sprint(redactor); got != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, got)
+	}
+}